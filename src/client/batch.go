@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+// batchAPIVersion is the API version of the work item $batch endpoint and of the requests it carries.
+const batchAPIVersion = "5.1"
+
+// WorkItemBatchOperation is one create or update sent through the $batch endpoint.
+type WorkItemBatchOperation struct {
+	// ID is the work item to update; zero creates a new work item of Type.
+	ID       int
+	Type     string
+	PatchDoc []webapi.JsonPatchOperation
+}
+
+// WorkItemBatchResult is the outcome of one batch operation. Each operation succeeds or fails on
+// its own: the $batch endpoint is not transactional.
+type WorkItemBatchResult struct {
+	WorkItem *workitemtracking.WorkItem
+	Err      error
+}
+
+// batchRequest is one request in the body of a $batch call.
+type batchRequest struct {
+	Method  string                      `json:"method"`
+	URI     string                      `json:"uri"`
+	Headers map[string]string           `json:"headers"`
+	Body    []webapi.JsonPatchOperation `json:"body"`
+}
+
+// batchResponse is the response to one request of a $batch call. Body holds JSON text.
+type batchResponse struct {
+	Code int    `json:"code"`
+	Body string `json:"body"`
+}
+
+// BatchWorkItems creates or updates work items through the _apis/wit/$batch endpoint, sending up
+// to 200 operations per call. Results are returned in the order of ops. An error is only returned
+// when a whole call fails; per-item failures are reported in the results.
+func (c *ADOClient) BatchWorkItems(ctx context.Context, ops []WorkItemBatchOperation) ([]WorkItemBatchResult, error) {
+	results := make([]WorkItemBatchResult, 0, len(ops))
+	for start := 0; start < len(ops); start += MaxBatchSize {
+		end := min(start+MaxBatchSize, len(ops))
+		batch, err := c.sendBatch(ctx, ops[start:end])
+		if err != nil {
+			return results, adoerrors.FormatADOError(err, "Sending work item batch")
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+// sendBatch sends a single $batch call and maps each response back to its operation.
+func (c *ADOClient) sendBatch(ctx context.Context, ops []WorkItemBatchOperation) ([]WorkItemBatchResult, error) {
+	requests := make([]batchRequest, len(ops))
+	for i, op := range ops {
+		requests[i] = batchRequest{
+			Method:  http.MethodPatch,
+			URI:     c.batchOperationURI(op),
+			Headers: map[string]string{"Content-Type": "application/json-patch+json"},
+			Body:    op.PatchDoc,
+		}
+	}
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.Connection.GetClientByUrl(c.batchBaseURL())
+	req, err := client.CreateRequestMessage(ctx, http.MethodPost, fmt.Sprintf("%s/_apis/wit/$batch?api-version=%s", c.batchBaseURL(), batchAPIVersion),
+		"", bytes.NewReader(body), "application/json", "application/json", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	var responses []batchResponse
+	if err := client.UnmarshalCollectionBody(resp, &responses); err != nil {
+		return nil, err
+	}
+	if len(responses) != len(ops) {
+		return nil, fmt.Errorf("expected %d responses, got %d", len(ops), len(responses))
+	}
+
+	results := make([]WorkItemBatchResult, len(ops))
+	for i, r := range responses {
+		results[i] = parseBatchResponse(r)
+	}
+	return results, nil
+}
+
+// batchBaseURL returns the organization URL that the $batch endpoint is under.
+func (c *ADOClient) batchBaseURL() string {
+	return fmt.Sprintf("%s/%s", c.BaseURL, c.Organization)
+}
+
+// batchOperationURI returns the project-relative URI that creates or updates the work item.
+func (c *ADOClient) batchOperationURI(op WorkItemBatchOperation) string {
+	if op.ID != 0 {
+		return fmt.Sprintf("/%s/_apis/wit/workitems/%d?api-version=%s", url.PathEscape(c.Project), op.ID, batchAPIVersion)
+	}
+	return fmt.Sprintf("/%s/_apis/wit/workitems/$%s?api-version=%s", url.PathEscape(c.Project), url.PathEscape(op.Type), batchAPIVersion)
+}
+
+// parseBatchResponse decodes the work item of a successful response, or the API error of a failed
+// one as a WrappedError, so the error classifiers apply to it.
+func parseBatchResponse(r batchResponse) WorkItemBatchResult {
+	if r.Code >= 200 && r.Code < 300 {
+		var workItem workitemtracking.WorkItem
+		if err := json.Unmarshal([]byte(r.Body), &workItem); err != nil {
+			return WorkItemBatchResult{Err: fmt.Errorf("Error decoding batch response: %w", err)}
+		}
+		return WorkItemBatchResult{WorkItem: &workItem}
+	}
+
+	var wrapped azuredevops.WrappedError
+	if err := json.Unmarshal([]byte(r.Body), &wrapped); err != nil || wrapped.Message == nil {
+		// Some errors nest the message in a "value" object, as the SDK's WrappedImproperError does.
+		var improper azuredevops.WrappedImproperError
+		if err := json.Unmarshal([]byte(r.Body), &improper); err == nil && improper.Value != nil && improper.Value.Message != nil {
+			wrapped.Message = improper.Value.Message
+		} else {
+			message := fmt.Sprintf("Request returned status: %d", r.Code)
+			wrapped.Message = &message
+		}
+	}
+	statusCode := r.Code
+	wrapped.StatusCode = &statusCode
+	return WorkItemBatchResult{Err: &wrapped}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+)
+
+func TestBatchWorkItems_ChunksAndMapsResponses(t *testing.T) {
+	var calls []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/org/_apis/wit/$batch" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+		var requests []batchRequest
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			t.Fatal(err)
+		}
+		calls = append(calls, len(requests))
+
+		responses := make([]batchResponse, len(requests))
+		for i, req := range requests {
+			title := req.Body[0].Value.(string)
+			if title == "fail" {
+				responses[i] = batchResponse{Code: 400, Body: `{"value":{"Message":"TF401320: Rule Error"}}`}
+				continue
+			}
+			id := len(calls)*1000 + i
+			responses[i] = batchResponse{Code: 200, Body: fmt.Sprintf(`{"id": %d, "fields": {"System.Title": %q}}`, id, title)}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"count": len(responses), "value": responses})
+	}))
+	defer server.Close()
+
+	client := &ADOClient{
+		BaseURL:      server.URL,
+		Organization: "org",
+		Project:      "My Project",
+		Connection:   azuredevops.NewPatConnection(server.URL+"/org", "pat"),
+	}
+	ops := make([]WorkItemBatchOperation, 250)
+	for i := range ops {
+		title := "item"
+		if i == 3 {
+			title = "fail"
+		}
+		ops[i] = WorkItemBatchOperation{Type: "Task", PatchDoc: []webapi.JsonPatchOperation{
+			patch.NewOperation(webapi.OperationValues.Add, "/fields/System.Title", title),
+		}}
+	}
+
+	results, err := client.BatchWorkItems(context.Background(), ops)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 2 || calls[0] != 200 || calls[1] != 50 {
+		t.Errorf("expected batches of 200 and 50, got %v", calls)
+	}
+	if len(results) != 250 || *results[0].WorkItem.Id != 1000 || *results[249].WorkItem.Id != 2049 {
+		t.Fatalf("results not mapped back in order")
+	}
+	if results[3].Err == nil || !adoerrors.IsValidationError(results[3].Err) || !strings.Contains(results[3].Err.Error(), "TF401320") {
+		t.Errorf("expected a validation error for item 3, got %v", results[3].Err)
+	}
+}
+
+func TestBatchOperationURI(t *testing.T) {
+	client := &ADOClient{Project: "My Project"}
+	if got := client.batchOperationURI(WorkItemBatchOperation{Type: "User Story"}); got != "/My%20Project/_apis/wit/workitems/$User%20Story?api-version=5.1" {
+		t.Errorf("create URI: got %s", got)
+	}
+	if got := client.batchOperationURI(WorkItemBatchOperation{ID: 7}); got != "/My%20Project/_apis/wit/workitems/7?api-version=5.1" {
+		t.Errorf("update URI: got %s", got)
+	}
+}
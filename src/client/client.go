@@ -0,0 +1,300 @@
+// Package client talks to the Azure DevOps work item tracking API on behalf of adowork.
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andreswebs/adowork/config"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/core"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/location"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+// MaxBatchSize is the maximum number of work items the API accepts in a single batch request.
+const MaxBatchSize = 200
+
+// classificationTreeDepth is the number of area or iteration levels fetched below the project root.
+const classificationTreeDepth = 20
+
+// ADOClient is the Azure DevOps API client structure using the official library.
+type ADOClient struct {
+	Organization string
+	Project      string
+	PAT          string
+	BaseURL      string
+	Connection   *azuredevops.Connection
+	WITClient    workitemtracking.Client
+	// retries is the policy set by WithRetries; nil sends requests without a retry layer.
+	retries *RetryPolicy
+}
+
+// Option configures the ADOClient created by New.
+type Option func(*ADOClient)
+
+// New creates a new ADOClient using the official azure-devops-go-api library.
+func New(c *config.Config, opts ...Option) (*ADOClient, error) {
+	_, err := c.CheckMissing()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	secret, err := c.ResolveCredential(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ado := &ADOClient{
+		Organization: c.Organization,
+		Project:      c.Project,
+		BaseURL:      c.BaseURL,
+	}
+	for _, opt := range opts {
+		opt(ado)
+	}
+
+	if c.UsesToken() {
+		ado.Connection = azuredevops.NewAnonymousConnection(c.OrganizationURL())
+		ado.Connection.AuthorizationString = "Bearer " + secret
+	} else {
+		ado.PAT = secret
+		ado.Connection = azuredevops.NewPatConnection(c.OrganizationURL(), ado.PAT)
+	}
+	if ado.retries != nil {
+		if err := ado.installRetries(ctx); err != nil {
+			return nil, adoerrors.FormatADOError(err, "Creating work item tracking client")
+		}
+	}
+	witClient, err := workitemtracking.NewClient(ctx, ado.Connection)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Creating work item tracking client")
+	}
+	ado.WITClient = witClient
+	return ado, nil
+}
+
+// CreateWorkItem creates a new work item using the official Azure DevOps Go API library.
+func (c *ADOClient) CreateWorkItem(ctx context.Context, workItemType string, patchDoc []webapi.JsonPatchOperation) (*workitemtracking.WorkItem, error) {
+	// Create the work item using the typed client
+	args := workitemtracking.CreateWorkItemArgs{
+		Document: &patchDoc,
+		Project:  &c.Project,
+		Type:     &workItemType,
+	}
+
+	workItem, err := c.WITClient.CreateWorkItem(ctx, args)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Creating work item")
+	}
+
+	return workItem, nil
+}
+
+// UpdateWorkItem applies a JSON patch document to an existing work item.
+func (c *ADOClient) UpdateWorkItem(ctx context.Context, workItemID int, patchDoc []webapi.JsonPatchOperation) (*workitemtracking.WorkItem, error) {
+	args := workitemtracking.UpdateWorkItemArgs{
+		Document: &patchDoc,
+		Id:       &workItemID,
+		Project:  &c.Project,
+	}
+
+	workItem, err := c.WITClient.UpdateWorkItem(ctx, args)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Updating work item")
+	}
+
+	return workItem, nil
+}
+
+// GetWorkItem fetches a single work item, including its relations.
+func (c *ADOClient) GetWorkItem(ctx context.Context, workItemID int) (*workitemtracking.WorkItem, error) {
+	args := workitemtracking.GetWorkItemArgs{
+		Id:      &workItemID,
+		Project: &c.Project,
+		Expand:  &workitemtracking.WorkItemExpandValues.Relations,
+	}
+
+	workItem, err := c.WITClient.GetWorkItem(ctx, args)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Getting work item")
+	}
+
+	return workItem, nil
+}
+
+// QueryByWiql runs a WIQL query in the configured project and returns the IDs of the matching work items, in query order.
+// A top of zero leaves the result size to the server default.
+func (c *ADOClient) QueryByWiql(ctx context.Context, query string, top int) ([]int, error) {
+	args := workitemtracking.QueryByWiqlArgs{
+		Wiql:    &workitemtracking.Wiql{Query: &query},
+		Project: &c.Project,
+	}
+	if top > 0 {
+		args.Top = &top
+	}
+
+	result, err := c.WITClient.QueryByWiql(idempotentRequest(ctx), args)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Running WIQL query")
+	}
+
+	var ids []int
+	if result != nil && result.WorkItems != nil {
+		for _, ref := range *result.WorkItems {
+			if ref.Id != nil {
+				ids = append(ids, *ref.Id)
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// GetWorkItemsBatch fetches up to 200 work items in a single request, limited to the given fields.
+func (c *ADOClient) GetWorkItemsBatch(ctx context.Context, ids []int, fields []string) ([]workitemtracking.WorkItem, error) {
+	args := workitemtracking.GetWorkItemsBatchArgs{
+		WorkItemGetRequest: &workitemtracking.WorkItemBatchGetRequest{
+			Ids:         &ids,
+			Fields:      &fields,
+			ErrorPolicy: &workitemtracking.WorkItemErrorPolicyValues.Omit,
+		},
+		Project: &c.Project,
+	}
+
+	workItems, err := c.WITClient.GetWorkItemsBatch(idempotentRequest(ctx), args)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Getting work items batch")
+	}
+	if workItems == nil {
+		return nil, nil
+	}
+
+	return *workItems, nil
+}
+
+// GetFields returns the definitions of all work item fields available in the configured project.
+func (c *ADOClient) GetFields(ctx context.Context) ([]workitemtracking.WorkItemField, error) {
+	args := workitemtracking.GetFieldsArgs{
+		Project: &c.Project,
+	}
+
+	fields, err := c.WITClient.GetFields(ctx, args)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Getting field definitions")
+	}
+	if fields == nil {
+		return nil, nil
+	}
+
+	return *fields, nil
+}
+
+// GetWorkItemTypes returns the work item types defined by the configured project's process.
+func (c *ADOClient) GetWorkItemTypes(ctx context.Context) ([]workitemtracking.WorkItemType, error) {
+	args := workitemtracking.GetWorkItemTypesArgs{
+		Project: &c.Project,
+	}
+
+	types, err := c.WITClient.GetWorkItemTypes(ctx, args)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Getting work item types")
+	}
+	if types == nil {
+		return nil, nil
+	}
+
+	return *types, nil
+}
+
+// GetWorkItemTypeFields returns the fields of a work item type, including their allowed values.
+func (c *ADOClient) GetWorkItemTypeFields(ctx context.Context, workItemType string) ([]workitemtracking.WorkItemTypeFieldWithReferences, error) {
+	args := workitemtracking.GetWorkItemTypeFieldsWithReferencesArgs{
+		Project: &c.Project,
+		Type:    &workItemType,
+		Expand:  &workitemtracking.WorkItemTypeFieldsExpandLevelValues.AllowedValues,
+	}
+
+	fields, err := c.WITClient.GetWorkItemTypeFieldsWithReferences(ctx, args)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Getting work item type fields")
+	}
+	if fields == nil {
+		return nil, nil
+	}
+
+	return *fields, nil
+}
+
+// GetClassificationTree returns the full area or iteration tree of the configured project.
+func (c *ADOClient) GetClassificationTree(ctx context.Context, group workitemtracking.TreeStructureGroup) (*workitemtracking.WorkItemClassificationNode, error) {
+	depth := classificationTreeDepth
+	args := workitemtracking.GetClassificationNodeArgs{
+		Project:        &c.Project,
+		StructureGroup: &group,
+		Depth:          &depth,
+	}
+
+	node, err := c.WITClient.GetClassificationNode(ctx, args)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Getting "+string(group)+" tree")
+	}
+
+	return node, nil
+}
+
+// ConnectionInfo describes the identity and project a client is connected to.
+type ConnectionInfo struct {
+	User        string
+	ProjectName string
+	ProjectID   string
+}
+
+// ValidateConnection verifies the credentials against the connection data endpoint and checks that the project exists.
+func (c *ADOClient) ValidateConnection(ctx context.Context) (*ConnectionInfo, error) {
+	data, err := location.NewClient(ctx, c.Connection).GetConnectionData(ctx, location.GetConnectionDataArgs{})
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Getting connection data")
+	}
+	user := data.AuthenticatedUser
+	if user == nil || (user.Descriptor != nil && strings.Contains(*user.Descriptor, "UnauthenticatedIdentity")) {
+		return nil, fmt.Errorf("Getting connection data failed: the credentials were not accepted by %s/%s", c.BaseURL, c.Organization)
+	}
+	info := &ConnectionInfo{}
+	if user.ProviderDisplayName != nil {
+		info.User = *user.ProviderDisplayName
+	}
+
+	coreClient, err := core.NewClient(ctx, c.Connection)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Creating core client")
+	}
+	project, err := coreClient.GetProject(ctx, core.GetProjectArgs{ProjectId: &c.Project})
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "Getting project")
+	}
+	if project.Name != nil {
+		info.ProjectName = *project.Name
+	}
+	if project.Id != nil {
+		info.ProjectID = project.Id.String()
+	}
+
+	return info, nil
+}
+
+// GetWorkItemURL returns the URL for accessing a work item in the Azure DevOps web interface.
+func (c *ADOClient) GetWorkItemURL(workItemID int) string {
+	return fmt.Sprintf("%s/%s/%s/_workitems/edit/%d",
+		c.BaseURL, c.Organization, c.Project, workItemID)
+}
+
+// WorkItemAPIURL returns the REST API URL for a work item, as used in relation links.
+func (c *ADOClient) WorkItemAPIURL(workItemID int) string {
+	return fmt.Sprintf("%s/%s/%s/_apis/wit/workItems/%d",
+		c.BaseURL, c.Organization, c.Project, workItemID)
+}
@@ -0,0 +1,396 @@
+// Package fake provides an in-memory implementation of client.ClientV3, so that code built on the
+// adowork client can be tested without an Azure DevOps organization.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andreswebs/adowork/client"
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+// Client keeps work items in memory and serves the process metadata it is given. Patch documents
+// are applied the way Azure DevOps applies them, and failures are reported as the same
+// azuredevops.WrappedError values, so the classifiers of package errors work on them.
+//
+// The zero value is ready to use. A Client is safe for concurrent use.
+type Client struct {
+	// BaseURL, Organization and Project form the work item URLs, as they do for client.ADOClient.
+	BaseURL      string
+	Organization string
+	Project      string
+
+	// Types, Fields, TypeFields, Areas and Iterations are returned by the matching Get methods.
+	// TypeFields is keyed by work item type name, matched case-insensitively.
+	Types      []workitemtracking.WorkItemType
+	Fields     []workitemtracking.WorkItemField
+	TypeFields map[string][]workitemtracking.WorkItemTypeFieldWithReferences
+	Areas      *workitemtracking.WorkItemClassificationNode
+	Iterations *workitemtracking.WorkItemClassificationNode
+
+	// Identities are searched by SearchIdentities.
+	Identities []client.Identity
+
+	// Query answers QueryByWiql, since the fake cannot run WIQL. When nil, every work item
+	// matches, in ID order.
+	Query func(query string) ([]int, error)
+
+	// Errors makes a method, named as in client.ClientV2 such as "CreateWorkItem", fail with the
+	// given error instead of doing its work.
+	Errors map[string]error
+
+	mu        sync.Mutex
+	workItems map[int]*workitemtracking.WorkItem
+	lastID    int
+}
+
+var _ client.ClientV3 = (*Client)(nil)
+
+// AddWorkItem stores a work item of the given type with the given fields, as if it had been
+// created earlier, and returns its ID.
+func (c *Client) AddWorkItem(workItemType string, fields map[string]interface{}) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	workItem := c.newWorkItem(workItemType)
+	maps.Copy(*workItem.Fields, fields)
+	c.workItems[*workItem.Id] = workItem
+	return *workItem.Id
+}
+
+// builder returns a real client, whose URLs need no connection.
+func (c *Client) builder() *client.ADOClient {
+	return &client.ADOClient{BaseURL: c.BaseURL, Organization: c.Organization, Project: c.Project}
+}
+
+// CreateWorkItem applies the patch document to a new work item. Like Azure DevOps, it rejects a
+// work item without a title.
+func (c *Client) CreateWorkItem(ctx context.Context, workItemType string, patchDoc []webapi.JsonPatchOperation) (*workitemtracking.WorkItem, error) {
+	if err := c.Errors["CreateWorkItem"]; err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	workItem := c.newWorkItem(workItemType)
+	if err := applyPatch(workItem, patchDoc); err != nil {
+		c.lastID--
+		return nil, err
+	}
+	if title, _ := (*workItem.Fields)["System.Title"].(string); title == "" {
+		c.lastID--
+		return nil, apiError(http.StatusBadRequest, "TF401320: Rule Error for field Title. Error code: Required, InvalidEmpty.")
+	}
+	c.workItems[*workItem.Id] = workItem
+	return copyWorkItem(workItem, nil), nil
+}
+
+// UpdateWorkItem applies the patch document to a stored work item and increments its revision.
+// A failed /rev test operation is reported as a conflict.
+func (c *Client) UpdateWorkItem(ctx context.Context, workItemID int, patchDoc []webapi.JsonPatchOperation) (*workitemtracking.WorkItem, error) {
+	if err := c.Errors["UpdateWorkItem"]; err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stored, ok := c.workItems[workItemID]
+	if !ok {
+		return nil, notFound(workItemID)
+	}
+	workItem := copyWorkItem(stored, nil)
+	if err := applyPatch(workItem, patchDoc); err != nil {
+		return nil, err
+	}
+	rev := *workItem.Rev + 1
+	workItem.Rev = &rev
+	(*workItem.Fields)["System.Rev"] = rev
+	c.workItems[workItemID] = workItem
+	return copyWorkItem(workItem, nil), nil
+}
+
+// BatchWorkItems sends each operation through CreateWorkItem or UpdateWorkItem. As with the
+// $batch endpoint, each operation succeeds or fails on its own.
+func (c *Client) BatchWorkItems(ctx context.Context, ops []client.WorkItemBatchOperation) ([]client.WorkItemBatchResult, error) {
+	if err := c.Errors["BatchWorkItems"]; err != nil {
+		return nil, err
+	}
+	results := make([]client.WorkItemBatchResult, len(ops))
+	for i, op := range ops {
+		if op.ID != 0 {
+			results[i].WorkItem, results[i].Err = c.UpdateWorkItem(ctx, op.ID, op.PatchDoc)
+		} else {
+			results[i].WorkItem, results[i].Err = c.CreateWorkItem(ctx, op.Type, op.PatchDoc)
+		}
+	}
+	return results, nil
+}
+
+// GetWorkItem returns a stored work item, including its relations.
+func (c *Client) GetWorkItem(ctx context.Context, workItemID int) (*workitemtracking.WorkItem, error) {
+	if err := c.Errors["GetWorkItem"]; err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	workItem, ok := c.workItems[workItemID]
+	if !ok {
+		return nil, notFound(workItemID)
+	}
+	return copyWorkItem(workItem, nil), nil
+}
+
+// QueryByWiql returns the IDs given by Query, or of every work item, limited to top when it is
+// greater than zero.
+func (c *Client) QueryByWiql(ctx context.Context, query string, top int) ([]int, error) {
+	if err := c.Errors["QueryByWiql"]; err != nil {
+		return nil, err
+	}
+	var ids []int
+	if c.Query != nil {
+		var err error
+		if ids, err = c.Query(query); err != nil {
+			return nil, err
+		}
+	} else {
+		c.mu.Lock()
+		ids = slices.Sorted(maps.Keys(c.workItems))
+		c.mu.Unlock()
+	}
+	if top > 0 && len(ids) > top {
+		ids = ids[:top]
+	}
+	return ids, nil
+}
+
+// GetWorkItemsBatch returns the stored work items in the order of ids, limited to the given
+// fields. Unknown IDs are omitted, as with the Omit error policy of client.ADOClient.
+func (c *Client) GetWorkItemsBatch(ctx context.Context, ids []int, fields []string) ([]workitemtracking.WorkItem, error) {
+	if err := c.Errors["GetWorkItemsBatch"]; err != nil {
+		return nil, err
+	}
+	if len(ids) > client.MaxBatchSize {
+		return nil, apiError(http.StatusBadRequest, fmt.Sprintf("The maximum number of work items in a batch is %d.", client.MaxBatchSize))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var workItems []workitemtracking.WorkItem
+	for _, id := range ids {
+		if workItem, ok := c.workItems[id]; ok {
+			workItems = append(workItems, *copyWorkItem(workItem, fields))
+		}
+	}
+	return workItems, nil
+}
+
+// GetFields returns Fields.
+func (c *Client) GetFields(ctx context.Context) ([]workitemtracking.WorkItemField, error) {
+	if err := c.Errors["GetFields"]; err != nil {
+		return nil, err
+	}
+	return c.Fields, nil
+}
+
+// GetWorkItemTypes returns Types.
+func (c *Client) GetWorkItemTypes(ctx context.Context) ([]workitemtracking.WorkItemType, error) {
+	if err := c.Errors["GetWorkItemTypes"]; err != nil {
+		return nil, err
+	}
+	return c.Types, nil
+}
+
+// GetWorkItemTypeFields returns the TypeFields entry of the work item type.
+func (c *Client) GetWorkItemTypeFields(ctx context.Context, workItemType string) ([]workitemtracking.WorkItemTypeFieldWithReferences, error) {
+	if err := c.Errors["GetWorkItemTypeFields"]; err != nil {
+		return nil, err
+	}
+	for name, fields := range c.TypeFields {
+		if strings.EqualFold(name, workItemType) {
+			return fields, nil
+		}
+	}
+	return nil, apiError(http.StatusNotFound, fmt.Sprintf("Work item type '%s' does not exist.", workItemType))
+}
+
+// GetClassificationTree returns Areas or Iterations.
+func (c *Client) GetClassificationTree(ctx context.Context, group workitemtracking.TreeStructureGroup) (*workitemtracking.WorkItemClassificationNode, error) {
+	if err := c.Errors["GetClassificationTree"]; err != nil {
+		return nil, err
+	}
+	if group == workitemtracking.TreeStructureGroupValues.Iterations {
+		return c.Iterations, nil
+	}
+	return c.Areas, nil
+}
+
+// SearchIdentities returns the Identities whose display name or unique name contains query,
+// case-insensitively.
+func (c *Client) SearchIdentities(ctx context.Context, query string) ([]client.Identity, error) {
+	if err := c.Errors["SearchIdentities"]; err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(query)
+	var identities []client.Identity
+	for _, i := range c.Identities {
+		if strings.Contains(strings.ToLower(i.DisplayName), query) || strings.Contains(strings.ToLower(i.UniqueName), query) {
+			identities = append(identities, i)
+		}
+	}
+	return identities, nil
+}
+
+// GetWorkItemURL returns the URL client.ADOClient would return.
+func (c *Client) GetWorkItemURL(workItemID int) string {
+	return c.builder().GetWorkItemURL(workItemID)
+}
+
+// WorkItemAPIURL returns the URL client.ADOClient would return.
+func (c *Client) WorkItemAPIURL(workItemID int) string {
+	return c.builder().WorkItemAPIURL(workItemID)
+}
+
+// newWorkItem allocates the next ID to a new work item at revision 1. The caller holds c.mu.
+func (c *Client) newWorkItem(workItemType string) *workitemtracking.WorkItem {
+	if c.workItems == nil {
+		c.workItems = make(map[int]*workitemtracking.WorkItem)
+	}
+	c.lastID++
+	id, rev := c.lastID, 1
+	fields := map[string]interface{}{
+		"System.Id":           id,
+		"System.Rev":          rev,
+		"System.WorkItemType": workItemType,
+		"System.TeamProject":  c.Project,
+	}
+	return &workitemtracking.WorkItem{Id: &id, Rev: &rev, Fields: &fields, Relations: &[]workitemtracking.WorkItemRelation{}}
+}
+
+// applyPatch applies the operations of a patch document to a work item, in order. It supports
+// every operation patch.Builder emits: add, replace, remove and test on fields, add on
+// /relations/-, remove, replace and test on /relations/<index>, and test on /rev and /id.
+func applyPatch(workItem *workitemtracking.WorkItem, patchDoc []webapi.JsonPatchOperation) error {
+	for _, op := range patchDoc {
+		if op.Op == nil || op.Path == nil {
+			return apiError(http.StatusBadRequest, "The patch operation is missing its op or path.")
+		}
+		path := *op.Path
+		switch {
+		case path == "/rev" && *op.Op == webapi.OperationValues.Test:
+			if fmt.Sprint(op.Value) != fmt.Sprint(*workItem.Rev) {
+				return apiError(http.StatusPreconditionFailed, fmt.Sprintf("TF26071: This work item has been changed by someone else since you opened it. Expected revision %v, found %d.", op.Value, *workItem.Rev))
+			}
+		case path == "/id" && *op.Op == webapi.OperationValues.Test:
+			if fmt.Sprint(op.Value) != fmt.Sprint(*workItem.Id) {
+				return testFailed(op)
+			}
+		case strings.HasPrefix(path, "/fields/"):
+			ref := strings.TrimPrefix(path, "/fields/")
+			switch *op.Op {
+			case webapi.OperationValues.Add, webapi.OperationValues.Replace:
+				(*workItem.Fields)[ref] = op.Value
+			case webapi.OperationValues.Remove:
+				delete(*workItem.Fields, ref)
+			case webapi.OperationValues.Test:
+				if value, ok := (*workItem.Fields)[ref]; !ok || fmt.Sprint(value) != fmt.Sprint(op.Value) {
+					return testFailed(op)
+				}
+			default:
+				return unsupported(op)
+			}
+		case path == "/relations/-" && *op.Op == webapi.OperationValues.Add:
+			relation, err := newRelation(op.Value)
+			if err != nil {
+				return err
+			}
+			*workItem.Relations = append(*workItem.Relations, relation)
+		case strings.HasPrefix(path, "/relations/"):
+			index, err := strconv.Atoi(strings.TrimPrefix(path, "/relations/"))
+			if err != nil || index < 0 || index >= len(*workItem.Relations) {
+				return apiError(http.StatusBadRequest, fmt.Sprintf("The work item has no relation at %s.", path))
+			}
+			switch *op.Op {
+			case webapi.OperationValues.Remove:
+				*workItem.Relations = slices.Delete(*workItem.Relations, index, index+1)
+			case webapi.OperationValues.Replace:
+				relation, err := newRelation(op.Value)
+				if err != nil {
+					return err
+				}
+				(*workItem.Relations)[index] = relation
+			case webapi.OperationValues.Test:
+				relation, err := newRelation(op.Value)
+				current := (*workItem.Relations)[index]
+				if err != nil || *relation.Rel != *current.Rel || *relation.Url != *current.Url {
+					return testFailed(op)
+				}
+			default:
+				return unsupported(op)
+			}
+		default:
+			return unsupported(op)
+		}
+	}
+	return nil
+}
+
+// newRelation decodes the value of a relation operation, an object with rel, url and optional attributes.
+func newRelation(value interface{}) (workitemtracking.WorkItemRelation, error) {
+	relation, ok := value.(map[string]interface{})
+	rel, _ := relation["rel"].(string)
+	url, _ := relation["url"].(string)
+	if !ok || rel == "" || url == "" {
+		return workitemtracking.WorkItemRelation{}, apiError(http.StatusBadRequest, "The relation value must be an object with rel and url.")
+	}
+	result := workitemtracking.WorkItemRelation{Rel: &rel, Url: &url}
+	if attrs, ok := relation["attributes"].(map[string]interface{}); ok {
+		result.Attributes = &attrs
+	}
+	return result, nil
+}
+
+// copyWorkItem returns a copy of a work item that shares no maps or slices with it. When fields
+// is not empty, only those fields are copied, and the relations are left out as in a batch read.
+func copyWorkItem(workItem *workitemtracking.WorkItem, fields []string) *workitemtracking.WorkItem {
+	id, rev := *workItem.Id, *workItem.Rev
+	copied := &workitemtracking.WorkItem{Id: &id, Rev: &rev}
+	values := maps.Clone(*workItem.Fields)
+	if len(fields) > 0 {
+		values = make(map[string]interface{})
+		for _, ref := range fields {
+			if v, ok := (*workItem.Fields)[ref]; ok {
+				values[ref] = v
+			}
+		}
+	} else if workItem.Relations != nil {
+		relations := slices.Clone(*workItem.Relations)
+		copied.Relations = &relations
+	}
+	copied.Fields = &values
+	return copied
+}
+
+// apiError returns an Azure DevOps API error with the given status and message.
+func apiError(status int, message string) error {
+	return &azuredevops.WrappedError{StatusCode: &status, Message: &message}
+}
+
+// notFound returns the error Azure DevOps reports for an unknown work item.
+func notFound(workItemID int) error {
+	return apiError(http.StatusNotFound, fmt.Sprintf("TF401232: Work item %d does not exist, or you do not have permissions to read it.", workItemID))
+}
+
+// unsupported returns the error for a patch operation the fake does not implement.
+func unsupported(op webapi.JsonPatchOperation) error {
+	return apiError(http.StatusBadRequest, fmt.Sprintf("Unsupported patch operation %s on %s.", *op.Op, *op.Path))
+}
+
+// testFailed returns the error of a test operation whose value does not match, as a conflict.
+func testFailed(op webapi.JsonPatchOperation) error {
+	return apiError(http.StatusPreconditionFailed, fmt.Sprintf("The test operation on %s failed: the value is not %v.", *op.Path, op.Value))
+}
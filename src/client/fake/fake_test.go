@@ -0,0 +1,206 @@
+package fake
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/andreswebs/adowork/client"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+)
+
+func TestClient_CreateUpdateAndGet(t *testing.T) {
+	ctx := context.Background()
+	c := &Client{BaseURL: "https://dev.azure.com", Organization: "org", Project: "proj"}
+
+	parent := c.AddWorkItem("Epic", map[string]interface{}{"System.Title": "Parent"})
+	patchDoc, err := patch.New().Title("Crash on save").AddRelation(patch.RelParent, c.WorkItemAPIURL(parent), nil).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := c.CreateWorkItem(ctx, "Bug", patchDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *created.Id != parent+1 || *created.Rev != 1 || (*created.Fields)["System.WorkItemType"] != "Bug" {
+		t.Errorf("expected Bug %d at revision 1, got %+v", parent+1, created)
+	}
+	if len(*created.Relations) != 1 || *(*created.Relations)[0].Url != "https://dev.azure.com/org/proj/_apis/wit/workItems/1" {
+		t.Errorf("expected a parent relation, got %+v", *created.Relations)
+	}
+
+	title, rev := "Crash on save as", 1
+	patchDoc, err = patch.New().Test("/rev", rev).ReplaceField(patch.FieldTitle, title).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdateWorkItem(ctx, *created.Id, patchDoc); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetWorkItem(ctx, *created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got.Rev != 2 || (*got.Fields)["System.Title"] != title {
+		t.Errorf("expected the new title at revision 2, got %+v", got.Fields)
+	}
+
+	// The same update now expects a stale revision.
+	if _, err := c.UpdateWorkItem(ctx, *created.Id, patchDoc); !adoerrors.IsConflictError(err) {
+		t.Errorf("expected a conflict, got %v", err)
+	}
+	if _, err := c.GetWorkItem(ctx, 99); err == nil {
+		t.Error("expected an error for an unknown work item")
+	}
+	if url := c.GetWorkItemURL(*created.Id); url != "https://dev.azure.com/org/proj/_workitems/edit/2" {
+		t.Errorf("unexpected URL %s", url)
+	}
+}
+
+func TestClient_AppliesEveryBuilderOperation(t *testing.T) {
+	ctx := context.Background()
+	c := &Client{BaseURL: "https://dev.azure.com", Organization: "org", Project: "proj"}
+	epic := c.AddWorkItem("Epic", map[string]interface{}{"System.Title": "Epic"})
+	feature := c.AddWorkItem("Feature", map[string]interface{}{"System.Title": "Feature"})
+	patchDoc, err := patch.New().
+		Title("Task").
+		Field("System.Tags", "a").
+		AddRelation(patch.RelParent, c.WorkItemAPIURL(epic), map[string]interface{}{"comment": "first"}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := c.CreateWorkItem(ctx, "Task", patchDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Move the task from the epic to the feature, guarded by tests on its ID, revision, a field and the relation.
+	patchDoc, err = patch.New().
+		Test("/id", *created.Id).
+		Test("/rev", 1).
+		Test("/fields/System.Tags", "a").
+		Operations(patch.NewOperation(webapi.OperationValues.Test, "/relations/0", map[string]interface{}{"rel": patch.RelParent, "url": c.WorkItemAPIURL(epic)})).
+		RemoveRelation(0).
+		AddRelation(patch.RelParent, c.WorkItemAPIURL(feature), nil).
+		ReplaceField("System.Tags", "b").
+		RemoveField("System.Tags").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := c.UpdateWorkItem(ctx, *created.Id, patchDoc)
+	if err != nil {
+		t.Fatalf("expected every operation of the builder to apply, got %v", err)
+	}
+	if relations := *updated.Relations; len(relations) != 1 || *relations[0].Url != c.WorkItemAPIURL(feature) {
+		t.Errorf("expected the feature as the only parent, got %+v", relations)
+	}
+	if _, ok := (*updated.Fields)["System.Tags"]; ok {
+		t.Errorf("expected the tags to be removed, got %v", (*updated.Fields)["System.Tags"])
+	}
+
+	// Failed tests are conflicts, and the work item is left unchanged.
+	for _, b := range []*patch.Builder{
+		patch.New().Test("/id", *created.Id+1),
+		patch.New().Test("/fields/System.Title", "Other"),
+		patch.New().Operations(patch.NewOperation(webapi.OperationValues.Test, "/relations/0", map[string]interface{}{"rel": patch.RelParent, "url": c.WorkItemAPIURL(epic)})),
+	} {
+		patchDoc, err := b.Title("Changed").Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.UpdateWorkItem(ctx, *created.Id, patchDoc); !adoerrors.IsConflictError(err) {
+			t.Errorf("expected %s to fail as a conflict, got %v", *patchDoc[0].Path, err)
+		}
+	}
+	patchDoc, _ = patch.New().RemoveRelation(5).Build()
+	if _, err := c.UpdateWorkItem(ctx, *created.Id, patchDoc); !adoerrors.IsValidationError(err) {
+		t.Errorf("expected removing a missing relation to be rejected, got %v", err)
+	}
+	if got, _ := c.GetWorkItem(ctx, *created.Id); *got.Rev != 2 || (*got.Fields)["System.Title"] != "Task" {
+		t.Errorf("expected the work item to be left at revision 2, got %+v", got)
+	}
+}
+
+func TestClient_RejectsMissingTitle(t *testing.T) {
+	c := &Client{}
+	_, err := c.CreateWorkItem(context.Background(), "Bug", []webapi.JsonPatchOperation{
+		patch.NewOperation(webapi.OperationValues.Add, "/fields/System.Description", "no title"),
+	})
+	if !adoerrors.IsValidationError(err) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if fields := adoerrors.ParseFieldErrors(err); len(fields) != 1 || fields[0].Field != "Title" {
+		t.Errorf("expected the title to be reported, got %+v", fields)
+	}
+	// The failed create does not use up an ID.
+	if id := c.AddWorkItem("Bug", nil); id != 1 {
+		t.Errorf("expected ID 1, got %d", id)
+	}
+}
+
+func TestClient_QueryAndBatch(t *testing.T) {
+	ctx := context.Background()
+	c := &Client{}
+	for _, title := range []string{"a", "b", "c"} {
+		c.AddWorkItem("Task", map[string]interface{}{"System.Title": title, "System.State": "New"})
+	}
+
+	ids, err := c.QueryByWiql(ctx, "SELECT [System.Id] FROM WorkItems", 2)
+	if err != nil || !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("expected the first two IDs, got %v, %v", ids, err)
+	}
+	c.Query = func(query string) ([]int, error) { return []int{3, 1}, nil }
+	if ids, _ := c.QueryByWiql(ctx, "", 0); !reflect.DeepEqual(ids, []int{3, 1}) {
+		t.Errorf("expected the IDs from Query, got %v", ids)
+	}
+
+	workItems, err := c.GetWorkItemsBatch(ctx, []int{3, 42, 1}, []string{"System.Title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workItems) != 2 || !reflect.DeepEqual(*workItems[0].Fields, map[string]interface{}{"System.Title": "c"}) {
+		t.Errorf("expected work items 3 and 1 with their titles only, got %+v", workItems)
+	}
+
+	results, err := c.BatchWorkItems(ctx, []client.WorkItemBatchOperation{
+		{Type: "Task", PatchDoc: []webapi.JsonPatchOperation{patch.NewOperation(webapi.OperationValues.Add, "/fields/System.Title", "d")}},
+		{ID: 42, PatchDoc: []webapi.JsonPatchOperation{patch.NewOperation(webapi.OperationValues.Add, "/fields/System.Title", "e")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Err != nil || *results[0].WorkItem.Id != 4 || results[1].Err == nil {
+		t.Errorf("expected the create to succeed and the unknown update to fail, got %+v", results)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	c := &Client{Errors: map[string]error{"GetWorkItemTypes": context.DeadlineExceeded}}
+	if _, err := c.GetWorkItemTypes(context.Background()); !adoerrors.IsNetworkError(err) {
+		t.Errorf("expected the configured error, got %v", err)
+	}
+	if _, err := c.GetFields(context.Background()); err != nil {
+		t.Errorf("expected other methods to succeed, got %v", err)
+	}
+}
+
+func TestClient_SearchIdentities(t *testing.T) {
+	c := &Client{Identities: []client.Identity{
+		{DisplayName: "Ana Lima", UniqueName: "ana@example.com"},
+		{DisplayName: "Bo Chen", UniqueName: "bo@example.com"},
+	}}
+	found, err := c.SearchIdentities(context.Background(), "ANA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].UniqueName != "ana@example.com" {
+		t.Errorf("expected the identity matching by name, got %+v", found)
+	}
+	if found, _ := c.SearchIdentities(context.Background(), "bo@"); len(found) != 1 {
+		t.Errorf("expected the identity matching by email, got %+v", found)
+	}
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/identity"
+)
+
+// identitySearchFilter matches identities by display name, account name or email.
+const identitySearchFilter string = "General"
+
+// Identity is a user or group that work items can be assigned to.
+type Identity struct {
+	DisplayName string
+	// UniqueName is the sign-in name, usually an email address, as set in System.AssignedTo.
+	UniqueName string
+}
+
+// String returns the identity as Azure DevOps shows it in identity fields: "Name <unique name>".
+func (i Identity) String() string {
+	if i.UniqueName == "" || i.UniqueName == i.DisplayName {
+		return i.DisplayName
+	}
+	return i.DisplayName + " <" + i.UniqueName + ">"
+}
+
+// SearchIdentities returns the active identities of the organization whose display name, account
+// name or email matches query.
+func (c *ADOClient) SearchIdentities(ctx context.Context, query string) ([]Identity, error) {
+	identityClient, err := identity.NewClient(ctx, c.Connection)
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "creating identity client")
+	}
+	filter, none := identitySearchFilter, identity.QueryMembershipValues.None
+	found, err := identityClient.ReadIdentities(ctx, identity.ReadIdentitiesArgs{
+		SearchFilter:    &filter,
+		FilterValue:     &query,
+		QueryMembership: &none,
+	})
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "searching identities")
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	var identities []Identity
+	for _, id := range *found {
+		if id.IsActive != nil && !*id.IsActive {
+			continue
+		}
+		i := Identity{UniqueName: identityProperty(id.Properties, "Mail")}
+		if i.UniqueName == "" {
+			i.UniqueName = identityProperty(id.Properties, "Account")
+		}
+		if id.ProviderDisplayName != nil {
+			i.DisplayName = *id.ProviderDisplayName
+		}
+		if id.CustomDisplayName != nil && *id.CustomDisplayName != "" {
+			i.DisplayName = *id.CustomDisplayName
+		}
+		if i.DisplayName == "" {
+			i.DisplayName = i.UniqueName
+		}
+		identities = append(identities, i)
+	}
+	return identities, nil
+}
+
+// identityProperty returns a string property of an identity. The API sends properties as
+// {"Name": {"$type": "System.String", "$value": "..."}}.
+func identityProperty(properties interface{}, name string) string {
+	props, ok := properties.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for key, prop := range props {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		if typed, ok := prop.(map[string]interface{}); ok {
+			value, _ := typed["$value"].(string)
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIdentityProperty(t *testing.T) {
+	var properties interface{}
+	data := `{"Mail": {"$type": "System.String", "$value": "ana@example.com"}, "Account": {"$type": "System.String", "$value": "ana"}}`
+	if err := json.Unmarshal([]byte(data), &properties); err != nil {
+		t.Fatal(err)
+	}
+	if got := identityProperty(properties, "mail"); got != "ana@example.com" {
+		t.Errorf("expected the mail property, got %q", got)
+	}
+	if got := identityProperty(properties, "Description"); got != "" {
+		t.Errorf("expected an empty string for a missing property, got %q", got)
+	}
+	if got := identityProperty(nil, "Mail"); got != "" {
+		t.Errorf("expected an empty string without properties, got %q", got)
+	}
+}
+
+func TestIdentity_String(t *testing.T) {
+	if got := (Identity{DisplayName: "Ana", UniqueName: "ana@example.com"}).String(); got != "Ana <ana@example.com>" {
+		t.Errorf("unexpected identity: %s", got)
+	}
+	if got := (Identity{DisplayName: "Build Service"}).String(); got != "Build Service" {
+		t.Errorf("unexpected identity: %s", got)
+	}
+}
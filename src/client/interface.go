@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+// ClientV1 is version 1 of the work item client API, implemented by ADOClient and by the fake in
+// package client/fake. Its method set is frozen: methods are only added in a new versioned
+// interface embedding this one, so implementations outside this module keep compiling.
+type ClientV1 interface {
+	CreateWorkItem(ctx context.Context, workItemType string, patchDoc []webapi.JsonPatchOperation) (*workitemtracking.WorkItem, error)
+	UpdateWorkItem(ctx context.Context, workItemID int, patchDoc []webapi.JsonPatchOperation) (*workitemtracking.WorkItem, error)
+	BatchWorkItems(ctx context.Context, ops []WorkItemBatchOperation) ([]WorkItemBatchResult, error)
+	GetWorkItem(ctx context.Context, workItemID int) (*workitemtracking.WorkItem, error)
+	QueryByWiql(ctx context.Context, query string, top int) ([]int, error)
+	GetWorkItemsBatch(ctx context.Context, ids []int, fields []string) ([]workitemtracking.WorkItem, error)
+	GetFields(ctx context.Context) ([]workitemtracking.WorkItemField, error)
+	GetWorkItemTypes(ctx context.Context) ([]workitemtracking.WorkItemType, error)
+	GetWorkItemTypeFields(ctx context.Context, workItemType string) ([]workitemtracking.WorkItemTypeFieldWithReferences, error)
+	GetClassificationTree(ctx context.Context, group workitemtracking.TreeStructureGroup) (*workitemtracking.WorkItemClassificationNode, error)
+	GetWorkItemURL(workItemID int) string
+}
+
+var _ ClientV1 = (*ADOClient)(nil)
+
+// ClientV2 is version 2 of the client API. It adds identity search to ClientV1, and is frozen in
+// the same way.
+type ClientV2 interface {
+	ClientV1
+	SearchIdentities(ctx context.Context, query string) ([]Identity, error)
+}
+
+var _ ClientV2 = (*ADOClient)(nil)
+
+// ClientV3 is version 3 of the client API. It adds the REST API URL of a work item, which
+// relations built with package patch point to, and is frozen in the same way.
+type ClientV3 interface {
+	ClientV2
+	WorkItemAPIURL(workItemID int) string
+}
+
+var _ ClientV3 = (*ADOClient)(nil)
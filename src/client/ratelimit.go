@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimitGate is shared by concurrent requests so that they back off together: when one of them
+// is rate limited, every request waits until the pause is over before being sent.
+type rateLimitGate struct {
+	mu    sync.Mutex
+	until time.Time
+	now   func() time.Time
+}
+
+// newRateLimitGate returns an open gate.
+func newRateLimitGate() *rateLimitGate {
+	return &rateLimitGate{now: time.Now}
+}
+
+// wait blocks until the gate is open or the context is done.
+func (g *rateLimitGate) wait(ctx context.Context) error {
+	for {
+		g.mu.Lock()
+		d := g.until.Sub(g.now())
+		g.mu.Unlock()
+		if d <= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+}
+
+// pause closes the gate for d, unless it is already closed for longer.
+func (g *rateLimitGate) pause(d time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if until := g.now().Add(d); until.After(g.until) {
+		g.until = until
+	}
+}
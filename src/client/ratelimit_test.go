@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimitGate_PauseBlocksEveryWorker(t *testing.T) {
+	gate := newRateLimitGate()
+	gate.pause(30 * time.Millisecond)
+	gate.pause(time.Millisecond) // a shorter pause does not reopen the gate early
+
+	start := time.Now()
+	if err := gate.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("expected wait to block for the pause, returned after %s", elapsed)
+	}
+
+	gate.pause(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := gate.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context error, got %v", err)
+	}
+}
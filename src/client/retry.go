@@ -0,0 +1,301 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/core"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/identity"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+const (
+	DefaultMaxRetries   int           = 3
+	DefaultRetryTimeout time.Duration = time.Minute
+	// retryBaseDelay is the backoff before the first retry; it doubles on each further retry.
+	retryBaseDelay = 500 * time.Millisecond
+	// retryMaxDelay caps the backoff between two attempts.
+	retryMaxDelay = 30 * time.Second
+)
+
+// RetryPolicy controls how failed requests to Azure DevOps are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt; zero disables retries.
+	MaxRetries int
+	// Timeout bounds the time spent on a request including its retries; zero means no bound.
+	Timeout time.Duration
+}
+
+// WithRetries sends the requests of the client through a retry layer following policy. Whatever
+// the policy, the requests of the client back off together when one of them is rate limited.
+func WithRetries(policy RetryPolicy) Option {
+	return func(c *ADOClient) {
+		c.retries = &policy
+	}
+}
+
+// installRetries puts the retry layer in front of the transport of each SDK client that c uses,
+// keeping the transport the SDK chose, e.g. for Connection.TlsConfig. It runs once, from New,
+// before the clients are shared. The connection caches one SDK client per service URL, and the
+// copies it hands out share their HTTP client, so the clients fetched from it later are covered.
+func (c *ADOClient) installRetries(ctx context.Context) error {
+	gate := newRateLimitGate()
+	install := func(sdk *azuredevops.Client) {
+		httpClient := sdkHTTPClient(sdk)
+		if httpClient == nil {
+			return
+		}
+		if _, ok := httpClient.Transport.(*retryTransport); ok {
+			return
+		}
+		base := httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		transport := newRetryTransport(base, *c.retries)
+		transport.gate = gate
+		httpClient.Transport = transport
+	}
+
+	// The client of the organization URL also looks up the URLs of the other services.
+	install(c.Connection.GetClientByUrl(c.Connection.BaseUrl))
+	install(c.Connection.GetClientByUrl(c.batchBaseURL()))
+	for _, area := range []uuid.UUID{workitemtracking.ResourceAreaId, core.ResourceAreaId, identity.ResourceAreaId} {
+		sdk, err := c.Connection.GetClientByResourceAreaId(ctx, area)
+		if err != nil {
+			return err
+		}
+		install(sdk)
+	}
+	return nil
+}
+
+// sdkHTTPClient returns the HTTP client an SDK client sends its requests with. The SDK keeps it
+// in the unexported field client and offers no hook to set a transport, while only the transport
+// sees the Retry-After and X-RateLimit-* headers of a response. This depends on the private layout
+// of azuredevops.Client in SDK v1.0.0-b5: if it changes, nil is returned, requests are sent
+// without retries and TestWithRetries_SetsTransportOfSDKClients fails.
+func sdkHTTPClient(sdk *azuredevops.Client) *http.Client {
+	if sdk == nil {
+		return nil
+	}
+	field := reflect.ValueOf(sdk).Elem().FieldByName("client")
+	if !field.IsValid() || field.Type() != reflect.TypeFor[*http.Client]() || field.IsNil() {
+		return nil
+	}
+	return (*http.Client)(field.UnsafePointer())
+}
+
+// retryTransport retries transient failures with jittered exponential backoff. Requests that may
+// not be repeated safely, such as work item creates, are only retried when the response confirms
+// they were not processed (HTTP 429) or when they never reached the server. A rate-limited
+// response pauses every request, even when it is not retried.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+	// gate is shared by all requests, so that concurrent requests back off together when rate limited.
+	gate   *rateLimitGate
+	now    func() time.Time
+	jitter func() float64
+}
+
+// newRetryTransport returns a retry layer sending requests through base.
+func newRetryTransport(base http.RoundTripper, policy RetryPolicy) *retryTransport {
+	return &retryTransport{base: base, policy: policy, gate: newRateLimitGate(), now: time.Now, jitter: rand.Float64}
+}
+
+// RoundTrip sends the request, retrying it as long as the policy allows. The timeout of the policy
+// is a deadline for every attempt, so a hung attempt cannot outlast it, and for reading the body.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.policy.Timeout <= 0 {
+		return t.roundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.policy.Timeout)
+	resp, err := t.roundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the context of a request once its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// roundTrip sends the request until it succeeds, fails permanently or runs out of retries.
+func (t *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := t.now()
+	for attempt := 0; ; attempt++ {
+		if err := t.gate.wait(ctx); err != nil {
+			return nil, err
+		}
+		attemptReq, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(attemptReq)
+		retry := attempt < t.policy.MaxRetries && shouldRetry(req, resp, err)
+		rateLimited := err == nil && resp.StatusCode == http.StatusTooManyRequests
+		if !retry && !rateLimited {
+			return resp, err
+		}
+		delay := t.retryDelay(resp, attempt)
+		if rateLimited {
+			t.gate.pause(delay)
+		}
+		if !retry || (t.policy.Timeout > 0 && t.now().Add(delay).Sub(start) > t.policy.Timeout) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		// A rate-limited request waits at the gate along with the others.
+		if !rateLimited {
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// rewindRequest returns the request to send for an attempt, with a fresh copy of the body on retries.
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// shouldRetry reports whether a failed attempt may be repeated.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+		if isIdempotentRequest(req) {
+			return isTransientNetworkError(err)
+		}
+		return isNotSentError(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		// A throttled request was rejected before being processed.
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotentRequest(req)
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before the next attempt. The Retry-After header takes
+// precedence, then X-RateLimit-Reset when no requests remain, then jittered exponential backoff.
+func (t *retryTransport) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After"), t.now()); ok {
+			return d
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+				return max(time.Unix(reset, 0).Sub(t.now()), 0)
+			}
+		}
+	}
+	backoff := min(retryBaseDelay<<attempt, retryMaxDelay)
+	// Full jitter in the upper half keeps concurrent clients from retrying in lockstep.
+	return backoff/2 + time.Duration(t.jitter()*float64(backoff/2))
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an HTTP date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d, or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type idempotentKey struct{}
+
+// idempotentRequest marks the requests sent with ctx as safe to repeat, for reads sent with POST
+// such as WIQL queries.
+func idempotentRequest(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// isIdempotentRequest reports whether repeating the request cannot change the outcome.
+func isIdempotentRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	marked, _ := req.Context().Value(idempotentKey{}).(bool)
+	return marked
+}
+
+// isTransientNetworkError returns true for timeouts, connection resets and connections closed early.
+func isTransientNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) || isNotSentError(err)
+}
+
+// isNotSentError returns true when the request failed before reaching the server, e.g. a DNS
+// failure or a refused connection.
+func isNotSentError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
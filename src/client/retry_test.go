@@ -0,0 +1,310 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+)
+
+// newTestRetryTransport returns a retry layer with a fixed jitter, so backoff delays are predictable.
+func newTestRetryTransport(policy RetryPolicy) *retryTransport {
+	transport := newRetryTransport(http.DefaultTransport, policy)
+	transport.jitter = func() float64 { return 0 }
+	return transport
+}
+
+// statusServer answers with the given statuses in turn, then with 200, and counts the requests.
+func statusServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		n := int(calls.Add(1))
+		if r.Method != http.MethodGet && string(body) != `{"title":"x"}` {
+			t.Errorf("attempt %d: expected the request body to be replayed, got %q", n, body)
+		}
+		if n <= len(statuses) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func sendThrough(t *testing.T, transport http.RoundTripper, ctx context.Context, method, url string) *http.Response {
+	t.Helper()
+	var body io.Reader
+	if method != http.MethodGet {
+		body = strings.NewReader(`{"title":"x"}`)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestRetryTransport_RetriesTransientStatuses(t *testing.T) {
+	server, calls := statusServer(t, http.Header{"Retry-After": {"0"}}, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	resp := sendThrough(t, newTestRetryTransport(RetryPolicy{MaxRetries: 3}), context.Background(), http.MethodGet, server.URL)
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("expected success on the third attempt, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryTransport_StopsAfterMaxRetries(t *testing.T) {
+	server, calls := statusServer(t, http.Header{"Retry-After": {"0"}}, 503, 503, 503, 503)
+	resp := sendThrough(t, newTestRetryTransport(RetryPolicy{MaxRetries: 2}), context.Background(), http.MethodGet, server.URL)
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 3 {
+		t.Errorf("expected the last failure after 3 attempts, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+
+	server, calls = statusServer(t, http.Header{"Retry-After": {"0"}}, 503)
+	sendThrough(t, newTestRetryTransport(RetryPolicy{}), context.Background(), http.MethodGet, server.URL)
+	if calls.Load() != 1 {
+		t.Errorf("expected no retries with --max-retries 0, got %d attempts", calls.Load())
+	}
+}
+
+func TestRetryTransport_RateLimitPausesWithoutRetries(t *testing.T) {
+	server, calls := statusServer(t, http.Header{"Retry-After": {"30"}}, http.StatusTooManyRequests)
+	transport := newTestRetryTransport(RetryPolicy{})
+	resp := sendThrough(t, transport, context.Background(), http.MethodGet, server.URL)
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 1 {
+		t.Errorf("expected the throttled response without retrying, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+
+	// The other requests back off even though the throttled one was not retried.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := transport.gate.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the gate to stay closed for the Retry-After delay, got %v", err)
+	}
+}
+
+func TestWithRetries_SetsTransportOfSDKClients(t *testing.T) {
+	// Each kind of request fails once with 503 before succeeding: the lookup of the API locations,
+	// the lookup of the service URLs, and a later request.
+	var calls sync.Map
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		n, _ := calls.LoadOrStore(key, new(atomic.Int32))
+		if n.(*atomic.Int32).Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch {
+		case r.Method == http.MethodOptions:
+			fmt.Fprint(w, `{"count":1,"value":[{"id":"e81700f7-3be2-46de-8624-2eb35882fcaa","area":"Location","resourceName":"ResourceAreas","routeTemplate":"_apis/{resource}/{areaId}","resourceVersion":1,"minVersion":"1.0","maxVersion":"5.1","releasedVersion":"0.0"}]}`)
+		case strings.Contains(r.URL.Path, "ResourceAreas"):
+			// Servers without resource areas, such as on-premises ones, serve everything from the organization URL.
+			fmt.Fprint(w, `{"count":0,"value":[]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	defaultTransport := http.DefaultTransport
+
+	// The server certificate is only trusted through the TLS configuration of the connection, which
+	// the SDK puts in the transport that the retry layer wraps.
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	c := &ADOClient{BaseURL: server.URL, Organization: "org", Connection: azuredevops.NewAnonymousConnection(server.URL + "/org")}
+	c.Connection.TlsConfig = &tls.Config{RootCAs: roots}
+	WithRetries(RetryPolicy{MaxRetries: 3})(c)
+	if err := c.installRetries(context.Background()); err != nil {
+		t.Fatalf("expected the service lookup to succeed once retried, got %v", err)
+	}
+
+	// The connection caches its clients, which share the HTTP client whose transport was wrapped.
+	sdk := c.Connection.GetClientByUrl(c.Connection.BaseUrl)
+	req, err := sdk.CreateRequestMessage(context.Background(), http.MethodGet, server.URL+"/org/_apis/wit/workitems/1", "", nil, "", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := sdk.SendRequest(req)
+	if err != nil {
+		t.Fatalf("expected the request to succeed once retried, got %v", err)
+	}
+	resp.Body.Close()
+	if n, _ := calls.Load("GET /org/_apis/wit/workitems/1"); n.(*atomic.Int32).Load() != 2 {
+		t.Errorf("expected the SDK client to retry, got %d attempts", n.(*atomic.Int32).Load())
+	}
+	if transport, ok := sdkHTTPClient(sdk).Transport.(*retryTransport); !ok || reflect.TypeOf(transport.base) != reflect.TypeFor[*http.Transport]() {
+		t.Errorf("expected the retry layer to wrap the transport of the SDK once, got %#v", sdkHTTPClient(sdk).Transport)
+	}
+	if http.DefaultTransport != defaultTransport {
+		t.Errorf("expected http.DefaultTransport to be left alone")
+	}
+}
+
+func TestRetryTransport_CreatesOnlyRetriedWhenRejected(t *testing.T) {
+	// A 503 does not tell whether the work item was created, so a create is not repeated.
+	server, calls := statusServer(t, http.Header{"Retry-After": {"0"}}, http.StatusServiceUnavailable)
+	resp := sendThrough(t, newTestRetryTransport(RetryPolicy{MaxRetries: 3}), context.Background(), http.MethodPost, server.URL)
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("expected the create not to be retried after a 503, got %d attempts", calls.Load())
+	}
+
+	// A throttled create was rejected before being processed.
+	server, calls = statusServer(t, http.Header{"Retry-After": {"0"}}, http.StatusTooManyRequests)
+	resp = sendThrough(t, newTestRetryTransport(RetryPolicy{MaxRetries: 3}), context.Background(), http.MethodPost, server.URL)
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("expected the throttled create to be retried, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+
+	// Reads sent with POST, such as WIQL queries, are marked as safe to repeat.
+	server, calls = statusServer(t, http.Header{"Retry-After": {"0"}}, http.StatusServiceUnavailable)
+	resp = sendThrough(t, newTestRetryTransport(RetryPolicy{MaxRetries: 3}), idempotentRequest(context.Background()), http.MethodPost, server.URL)
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("expected the query to be retried, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryTransport_RetryTimeout(t *testing.T) {
+	// The server asks for a longer wait than the retry timeout allows, so the failure is returned at once.
+	server, calls := statusServer(t, http.Header{"Retry-After": {"120"}}, http.StatusTooManyRequests)
+	start := time.Now()
+	resp := sendThrough(t, newTestRetryTransport(RetryPolicy{MaxRetries: 3, Timeout: time.Second}), context.Background(), http.MethodGet, server.URL)
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 1 {
+		t.Errorf("expected the throttled response without retrying, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected no wait, returned after %s", elapsed)
+	}
+}
+
+func TestRetryTransport_RetryTimeoutBoundsEachAttempt(t *testing.T) {
+	// The first attempt hangs until the client gives up on it.
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err = (&http.Client{Transport: newTestRetryTransport(RetryPolicy{MaxRetries: 3, Timeout: 200 * time.Millisecond})}).Do(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the retry timeout to end the hung attempt, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected to give up after the retry timeout, returned after %s", elapsed)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected no retry after the retry timeout, got %d attempts", calls.Load())
+	}
+}
+
+func TestRetryTransport_ConnectionErrors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + listener.Addr().String()
+	listener.Close() // connections are refused from now on
+
+	var calls atomic.Int32
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	transport := newRetryTransport(base, RetryPolicy{MaxRetries: 2})
+	transport.jitter = func() float64 { return 0 }
+
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(`{"title":"x"}`))
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("expected a connection error")
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected a refused create to be retried, as it never reached the server, got %d attempts", calls.Load())
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestShouldRetry_NetworkErrors(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "https://dev.azure.com", nil)
+	post, _ := http.NewRequest(http.MethodPost, "https://dev.azure.com", strings.NewReader("{}"))
+	timeout := &net.OpError{Op: "read", Net: "tcp", Err: &net.DNSError{IsTimeout: true}}
+	dial := &net.OpError{Op: "dial", Net: "tcp", Err: io.EOF}
+
+	if !shouldRetry(get, nil, io.ErrUnexpectedEOF) {
+		t.Error("expected a read to be retried after the connection closed early")
+	}
+	if !shouldRetry(get, nil, timeout) {
+		t.Error("expected a read to be retried after a timeout")
+	}
+	if shouldRetry(post, nil, io.ErrUnexpectedEOF) {
+		t.Error("expected a create not to be retried when it may have reached the server")
+	}
+	if !shouldRetry(post, nil, dial) {
+		t.Error("expected a create to be retried when the connection could not be opened")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	transport := newTestRetryTransport(RetryPolicy{})
+	transport.now = func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		attempt int
+		want    time.Duration
+	}{
+		{"retry-after seconds", http.Header{"Retry-After": {"7"}}, 0, 7 * time.Second},
+		{"retry-after date", http.Header{"Retry-After": {now.Add(10 * time.Second).Format(http.TimeFormat)}}, 0, 10 * time.Second},
+		{"rate limit reset", http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1714564830"}}, 0, 30 * time.Second},
+		{"requests remaining", http.Header{"X-Ratelimit-Remaining": {"5"}, "X-Ratelimit-Reset": {"1714564830"}}, 0, retryBaseDelay / 2},
+		{"backoff", nil, 2, 2 * retryBaseDelay},
+		{"backoff cap", nil, 20, retryMaxDelay / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := transport.retryDelay(&http.Response{Header: tt.header}, tt.attempt)
+			if got != tt.want {
+				t.Errorf("retryDelay() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	transport.jitter = func() float64 { return 0.999 }
+	if got := transport.retryDelay(&http.Response{}, 1); got < retryBaseDelay || got >= 2*retryBaseDelay {
+		t.Errorf("expected the jittered delay within [%s, %s), got %s", retryBaseDelay, 2*retryBaseDelay, got)
+	}
+}
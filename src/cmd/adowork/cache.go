@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/config"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	EnvCacheTTL     string        = "ADOWORK_CACHE_TTL"
+	defaultCacheTTL time.Duration = 24 * time.Hour
+	cacheFileExt    string        = ".json"
+)
+
+// cacheKey identifies the Azure DevOps project a cache entry belongs to.
+type cacheKey struct {
+	BaseURL      string `json:"baseUrl"`
+	Organization string `json:"organization"`
+	Project      string `json:"project"`
+}
+
+// cacheEnvelope is the on-disk format of a single cache entry.
+type cacheEnvelope[T any] struct {
+	Key       cacheKey  `json:"key"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Data      T         `json:"data"`
+}
+
+// metadataCache stores process metadata on disk under
+// <cache root>/adowork/<host>-<hash>/<org>/<project>/ (see cacheRootDir), where the hash is that of the base URL, so
+// that projects of the same name on different servers have their own directories. Each entry also
+// records the full cache key, so an entry that does not belong to the project is treated as a miss.
+type metadataCache struct {
+	dir string
+	key cacheKey
+	ttl time.Duration
+	now func() time.Time
+}
+
+// newMetadataCache returns the metadata cache for the configured project. The organization and the
+// project must be set, so that the cache directory is never that of several projects.
+func newMetadataCache(cfg *config.Config) (*metadataCache, error) {
+	var missing []string
+	if cfg.Organization == "" {
+		missing = append(missing, config.EnvADOOrg)
+	}
+	if cfg.Project == "" {
+		missing = append(missing, config.EnvADOProject)
+	}
+	if len(missing) > 0 {
+		return nil, &adoerrors.ConfigError{Err: fmt.Errorf("The metadata cache is kept per project. Set %s.", strings.Join(missing, " and "))}
+	}
+	root, err := cacheRootDir()
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := cacheTTLFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return &metadataCache{
+		dir: filepath.Join(root, "adowork", serverCacheDir(cfg.BaseURL), url.PathEscape(cfg.Organization), url.PathEscape(cfg.Project)),
+		key: cacheKey{BaseURL: cfg.BaseURL, Organization: cfg.Organization, Project: cfg.Project},
+		ttl: ttl,
+		now: time.Now,
+	}, nil
+}
+
+// cacheRootDir returns $XDG_CACHE_HOME when it is set to an absolute path, on every platform, and
+// otherwise the platform's user cache directory, e.g. ~/Library/Caches on macOS.
+func cacheRootDir() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("Unable to locate cache directory: %w", err)
+	}
+	return dir, nil
+}
+
+// serverCacheDir returns the name of the cache directory of a server: its host, for readability,
+// followed by a hash of the full base URL, which tells apart servers on the same host.
+func serverCacheDir(baseURL string) string {
+	host := "server"
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	sum := sha256.Sum256([]byte(strings.TrimRight(baseURL, "/")))
+	return url.PathEscape(host) + "-" + hex.EncodeToString(sum[:4])
+}
+
+// cacheTTLFromEnv reads the cache TTL from ADOWORK_CACHE_TTL. A TTL of zero disables the cache.
+func cacheTTLFromEnv() (time.Duration, error) {
+	v := os.Getenv(EnvCacheTTL)
+	if v == "" {
+		return defaultCacheTTL, nil
+	}
+	ttl, err := time.ParseDuration(v)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("Invalid %s: '%s'. Use a duration such as '12h' or '0' to disable caching.", EnvCacheTTL, v)
+	}
+	return ttl, nil
+}
+
+// path returns the file path of the named cache entry.
+func (m *metadataCache) path(name string) string {
+	return filepath.Join(m.dir, url.PathEscape(name)+cacheFileExt)
+}
+
+// clear removes all cache entries of the project.
+func (m *metadataCache) clear() error {
+	if err := os.RemoveAll(m.dir); err != nil {
+		return fmt.Errorf("Error clearing cache: %w", err)
+	}
+	return nil
+}
+
+// readCacheEntry returns the named entry if it exists, belongs to this project and has not expired.
+func readCacheEntry[T any](m *metadataCache, name string) (T, bool) {
+	var env cacheEnvelope[T]
+	data, err := os.ReadFile(m.path(name))
+	if err != nil {
+		return env.Data, false
+	}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return env.Data, false
+	}
+	if env.Key != m.key || m.now().Sub(env.FetchedAt) > m.ttl {
+		return env.Data, false
+	}
+	return env.Data, true
+}
+
+// writeCacheEntry stores the named entry, replacing any previous value atomically.
+func writeCacheEntry[T any](m *metadataCache, name string, value T) error {
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cacheEnvelope[T]{Key: m.key, FetchedAt: m.now(), Data: value})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(m.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), m.path(name))
+}
+
+// cached returns the named entry from the cache, or fetches and stores it on a miss.
+// Failing to write the cache is not fatal: the fetched value is still returned.
+func cached[T any](m *metadataCache, name string, fetch func() (T, error)) (T, error) {
+	if value, ok := readCacheEntry[T](m, name); ok {
+		return value, nil
+	}
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+	storeCacheEntry(m, name, value)
+	return value, nil
+}
+
+// storeCacheEntry stores the named entry, only warning in debug mode if it cannot be written.
+func storeCacheEntry[T any](m *metadataCache, name string, value T) {
+	if err := writeCacheEntry(m, name, value); err != nil && os.Getenv("DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "Warning: unable to write cache entry %s: %v\n", name, err)
+	}
+}
+
+// cachingClient serves process metadata from the on-disk cache and delegates everything else.
+type cachingClient struct {
+	adoclient.ClientV1
+	cache *metadataCache
+}
+
+// withMetadataCache wraps the client with the metadata cache, unless caching is disabled or unavailable.
+func withMetadataCache(client adoclient.ClientV1, cfg *config.Config) adoclient.ClientV1 {
+	cache, err := newMetadataCache(cfg)
+	if err != nil || cache.ttl == 0 {
+		return client
+	}
+	return &cachingClient{ClientV1: client, cache: cache}
+}
+
+// GetWorkItemTypes returns the cached work item types.
+func (c *cachingClient) GetWorkItemTypes(ctx context.Context) ([]workitemtracking.WorkItemType, error) {
+	return cached(c.cache, "types", func() ([]workitemtracking.WorkItemType, error) {
+		return c.ClientV1.GetWorkItemTypes(ctx)
+	})
+}
+
+// refreshWorkItemTypes fetches the work item types from the server, bypassing the cache, and
+// stores them.
+func (c *cachingClient) refreshWorkItemTypes(ctx context.Context) ([]workitemtracking.WorkItemType, error) {
+	types, err := c.ClientV1.GetWorkItemTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	storeCacheEntry(c.cache, "types", types)
+	return types, nil
+}
+
+// GetFields returns the cached field definitions.
+func (c *cachingClient) GetFields(ctx context.Context) ([]workitemtracking.WorkItemField, error) {
+	return cached(c.cache, "fields", func() ([]workitemtracking.WorkItemField, error) {
+		return c.ClientV1.GetFields(ctx)
+	})
+}
+
+// GetWorkItemTypeFields returns the cached fields and allowed values of a work item type.
+func (c *cachingClient) GetWorkItemTypeFields(ctx context.Context, workItemType string) ([]workitemtracking.WorkItemTypeFieldWithReferences, error) {
+	return cached(c.cache, "typefields-"+strings.ToLower(workItemType), func() ([]workitemtracking.WorkItemTypeFieldWithReferences, error) {
+		return c.ClientV1.GetWorkItemTypeFields(ctx, workItemType)
+	})
+}
+
+// GetClassificationTree returns the cached area or iteration tree.
+func (c *cachingClient) GetClassificationTree(ctx context.Context, group workitemtracking.TreeStructureGroup) (*workitemtracking.WorkItemClassificationNode, error) {
+	return cached(c.cache, string(group), func() (*workitemtracking.WorkItemClassificationNode, error) {
+		return c.ClientV1.GetClassificationTree(ctx, group)
+	})
+}
+
+// SearchIdentities searches identities through the wrapped client, which must implement
+// adoclient.ClientV2. Identities are not cached.
+func (c *cachingClient) SearchIdentities(ctx context.Context, query string) ([]adoclient.Identity, error) {
+	v2, ok := c.ClientV1.(adoclient.ClientV2)
+	if !ok {
+		return nil, fmt.Errorf("Identity search is not supported by this client")
+	}
+	return v2.SearchIdentities(ctx, query)
+}
+
+// WorkItemAPIURL returns the REST API URL of a work item from the wrapped client, which must
+// implement adoclient.ClientV3.
+func (c *cachingClient) WorkItemAPIURL(workItemID int) string {
+	v3, ok := c.ClientV1.(adoclient.ClientV3)
+	if !ok {
+		return ""
+	}
+	return v3.WorkItemAPIURL(workItemID)
+}
+
+// refresh clears the cache and fetches every kind of metadata again.
+func (c *cachingClient) refresh(ctx context.Context) error {
+	if err := c.cache.clear(); err != nil {
+		return err
+	}
+	types, err := c.GetWorkItemTypes(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := c.GetFields(ctx); err != nil {
+		return err
+	}
+	for _, name := range workItemTypeNames(types) {
+		if _, err := c.GetWorkItemTypeFields(ctx, name); err != nil {
+			return err
+		}
+	}
+	for _, group := range []workitemtracking.TreeStructureGroup{
+		workitemtracking.TreeStructureGroupValues.Areas,
+		workitemtracking.TreeStructureGroupValues.Iterations,
+	} {
+		if _, err := c.GetClassificationTree(ctx, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cacheEntryInfo describes a cache entry for `cache show`.
+type cacheEntryInfo struct {
+	Name      string
+	FetchedAt time.Time
+	Expired   bool
+}
+
+// entries lists the cache entries of the project, sorted by name.
+func (m *metadataCache) entries() ([]cacheEntryInfo, error) {
+	files, err := os.ReadDir(m.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading cache directory: %w", err)
+	}
+
+	var infos []cacheEntryInfo
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), cacheFileExt) || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(m.dir, f.Name()))
+		if err != nil {
+			continue
+		}
+		var env cacheEnvelope[json.RawMessage]
+		if err := json.Unmarshal(data, &env); err != nil {
+			continue
+		}
+		name, err := url.PathUnescape(strings.TrimSuffix(f.Name(), cacheFileExt))
+		if err != nil {
+			name = f.Name()
+		}
+		infos = append(infos, cacheEntryInfo{
+			Name:      name,
+			FetchedAt: env.FetchedAt,
+			Expired:   env.Key != m.key || m.now().Sub(env.FetchedAt) > m.ttl,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// cacheCommand returns the `cache` subcommand, which manages the on-disk metadata cache.
+func cacheCommand(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "Manage the cached process metadata (types, fields, areas, iterations)",
+		Commands: []*cli.Command{
+			{
+				Name:  "refresh",
+				Usage: "Fetch all metadata for the project and store it in the cache",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cache, err := newMetadataCache(cfg)
+					if err != nil {
+						return err
+					}
+					client, err := adoclient.New(cfg, retryOption(cmd))
+					if err != nil {
+						return adoerrors.FormatADOError(err, "creating ADO client")
+					}
+					if err := (&cachingClient{ClientV1: client, cache: cache}).refresh(ctx); err != nil {
+						return err
+					}
+					fmt.Printf("Cache refreshed: %s\n", cache.dir)
+					return nil
+				},
+			},
+			{
+				Name:  "clear",
+				Usage: "Remove the cached metadata for the project",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cache, err := newMetadataCache(cfg)
+					if err != nil {
+						return err
+					}
+					if err := cache.clear(); err != nil {
+						return err
+					}
+					fmt.Printf("Cache cleared: %s\n", cache.dir)
+					return nil
+				},
+			},
+			{
+				Name:  "show",
+				Usage: "List the cached metadata for the project",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cache, err := newMetadataCache(cfg)
+					if err != nil {
+						return err
+					}
+					infos, err := cache.entries()
+					if err != nil {
+						return err
+					}
+					printCacheEntries(os.Stdout, cache, infos)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// printCacheEntries prints the cache location, TTL and the age of each entry.
+func printCacheEntries(w io.Writer, cache *metadataCache, infos []cacheEntryInfo) {
+	fmt.Fprintf(w, "Cache directory: %s\n", cache.dir)
+	if cache.ttl == 0 {
+		fmt.Fprintf(w, "TTL: disabled (%s=0)\n", EnvCacheTTL)
+	} else {
+		fmt.Fprintf(w, "TTL: %s\n", cache.ttl)
+	}
+	if len(infos) == 0 {
+		fmt.Fprintln(w, "No cached entries.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENTRY\tFETCHED\tSTATUS")
+	for _, info := range infos {
+		status := "fresh"
+		if info.Expired {
+			status = "expired"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", info.Name, info.FetchedAt.Local().Format(time.DateTime), status)
+	}
+	tw.Flush()
+}
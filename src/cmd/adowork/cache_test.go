@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreswebs/adowork/client/fake"
+	"github.com/andreswebs/adowork/config"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+)
+
+func newTestCache(t *testing.T, now *time.Time) *metadataCache {
+	t.Helper()
+	return &metadataCache{
+		dir: t.TempDir(),
+		key: cacheKey{BaseURL: "https://dev.azure.com", Organization: "org", Project: "proj"},
+		ttl: time.Hour,
+		now: func() time.Time { return *now },
+	}
+}
+
+func TestCachingClient_ServesFromCacheUntilExpired(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	cache := newTestCache(t, &now)
+
+	api := &fake.Client{Types: makeWorkItemTypes("Task", "Bug")}
+	client := &cachingClient{ClientV1: api, cache: cache}
+
+	for i := 0; i < 3; i++ {
+		types, err := client.GetWorkItemTypes(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(types) != 2 || *types[1].Name != "Bug" {
+			t.Fatalf("unexpected types: %v", workItemTypeNames(types))
+		}
+		// Types added after the first call are not seen while the cache is fresh.
+		api.Types = makeWorkItemTypes("Task", "Bug", "Epic")
+	}
+
+	now = now.Add(2 * time.Hour)
+	types, err := client.GetWorkItemTypes(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(types) != 3 {
+		t.Errorf("expected the expired entry to be fetched again, got %v", workItemTypeNames(types))
+	}
+}
+
+func TestAction_RefetchesTypesMissingFromCache(t *testing.T) {
+	now := time.Now()
+	cache := newTestCache(t, &now)
+	api := newFakeClient()
+	api.Types = makeWorkItemTypes("Task")
+	client := &cachingClient{ClientV1: api, cache: cache}
+	if _, err := client.GetWorkItemTypes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	api.Types = makeWorkItemTypes("Task", "Risk")
+
+	run := func(witType string) error {
+		cmd := &cli.Command{
+			Flags: []cli.Flag{&cli.StringFlag{Name: "type"}, &cli.StringFlag{Name: "title"}},
+			Action: func(ctx context.Context, cmd *cli.Command) error {
+				return actionWithClient(ctx, cmd, client, config.ProfileDefaults{})
+			},
+		}
+		return cmd.Run(context.Background(), []string{"", "--type", witType, "--title", "New"})
+	}
+	if err := run("risk"); err != nil {
+		t.Fatalf("expected a type added since the cache was filled to be accepted, got %v", err)
+	}
+	if types, _ := readCacheEntry[[]workitemtracking.WorkItemType](cache, "types"); len(types) != 2 {
+		t.Errorf("expected the refetched types to be cached, got %v", workItemTypeNames(types))
+	}
+	err := run("Rsk")
+	if err == nil || err.Error() != "Invalid work item type: 'Rsk'. Did you mean 'Risk'? Available types: Task, Risk." {
+		t.Errorf("expected an unknown type to be reported with the fresh list, got %v", err)
+	}
+}
+
+func TestCachingClient_KeyMismatchIsMiss(t *testing.T) {
+	now := time.Now()
+	cache := newTestCache(t, &now)
+	if err := writeCacheEntry(cache, "types", makeWorkItemTypes("Task")); err != nil {
+		t.Fatal(err)
+	}
+
+	other := *cache
+	other.key.BaseURL = "https://tfs.example.com"
+	if _, ok := readCacheEntry[[]workitemtracking.WorkItemType](&other, "types"); ok {
+		t.Errorf("expected an entry written for another server to be a miss")
+	}
+	if _, ok := readCacheEntry[[]workitemtracking.WorkItemType](cache, "types"); !ok {
+		t.Errorf("expected the entry to be a hit for its own key")
+	}
+}
+
+func TestMetadataCache_ClearAndEntries(t *testing.T) {
+	now := time.Now()
+	cache := newTestCache(t, &now)
+	for _, name := range []string{"types", "fields"} {
+		if err := writeCacheEntry(cache, name, []string{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	infos, err := cache.entries()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(infos) != 2 || infos[0].Name != "fields" || infos[1].Name != "types" || infos[0].Expired {
+		t.Errorf("unexpected entries: %+v", infos)
+	}
+
+	if err := cache.clear(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	infos, err = cache.entries()
+	if err != nil || len(infos) != 0 {
+		t.Errorf("expected no entries after clear, got %+v, %v", infos, err)
+	}
+}
+
+func TestCacheTTLFromEnv(t *testing.T) {
+	t.Setenv(EnvCacheTTL, "")
+	if ttl, err := cacheTTLFromEnv(); err != nil || ttl != defaultCacheTTL {
+		t.Errorf("default TTL: got %v, %v", ttl, err)
+	}
+	t.Setenv(EnvCacheTTL, "0")
+	if ttl, err := cacheTTLFromEnv(); err != nil || ttl != 0 {
+		t.Errorf("disabled TTL: got %v, %v", ttl, err)
+	}
+	t.Setenv(EnvCacheTTL, "soon")
+	if _, err := cacheTTLFromEnv(); err == nil {
+		t.Errorf("expected error for invalid TTL")
+	}
+}
+
+func TestCacheRootDir(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", xdg)
+	if dir, err := cacheRootDir(); err != nil || dir != xdg {
+		t.Errorf("expected XDG_CACHE_HOME to be used, got %q, %v", dir, err)
+	}
+
+	// A relative path is invalid under the XDG specification and is ignored.
+	t.Setenv("XDG_CACHE_HOME", "relative")
+	if dir, err := cacheRootDir(); err == nil && dir == "relative" {
+		t.Errorf("expected a relative XDG_CACHE_HOME to be ignored")
+	}
+}
+
+func TestNewMetadataCache_Directory(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", xdg)
+	t.Setenv(EnvCacheTTL, "")
+
+	for _, cfg := range []*config.Config{
+		{BaseURL: config.DefaultBaseURL, Project: "proj"},
+		{BaseURL: config.DefaultBaseURL, Organization: "org"},
+	} {
+		if _, err := newMetadataCache(cfg); !adoerrors.IsConfigError(err) {
+			t.Errorf("expected a config error without an organization or project, got %v", err)
+		}
+	}
+
+	cloud, err := newMetadataCache(&config.Config{BaseURL: config.DefaultBaseURL, Organization: "org", Project: "proj"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := newMetadataCache(&config.Config{BaseURL: "https://dev.azure.com/tfs", Organization: "org", Project: "proj"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(cloud.dir, filepath.Join(xdg, "adowork")) {
+		t.Errorf("expected the cache under XDG_CACHE_HOME, got %s", cloud.dir)
+	}
+	if cloud.dir == server.dir {
+		t.Errorf("expected servers to have their own cache directories, both got %s", cloud.dir)
+	}
+	if !strings.Contains(cloud.dir, "dev.azure.com-") || !strings.HasSuffix(cloud.dir, filepath.Join("org", "proj")) {
+		t.Errorf("unexpected cache directory %s", cloud.dir)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/config"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/andreswebs/adowork/markup"
+	"github.com/urfave/cli/v3"
+)
+
+// configCommand returns the `config` subcommand, which inspects and edits the configuration.
+func configCommand(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "Inspect and edit the configuration",
+		Commands: []*cli.Command{
+			{
+				Name:  "view",
+				Usage: "Show the resolved configuration and where each value comes from",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					printConfig(os.Stdout, cfg)
+					return nil
+				},
+			},
+			{
+				Name:      "set",
+				Usage:     "Set a value in a config file profile (the selected one, or --profile)",
+				ArgsUsage: "<key> <value>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 2 {
+						return usageErrorf("Usage: adowork config set <key> <value>. Valid keys: %s", strings.Join(config.ProfileKeys, ", "))
+					}
+					profile, err := setConfigValue(cfg.ConfigPath, cfg.Profile, cmd.Args().Get(0), cmd.Args().Get(1))
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Set %s in profile %q of %s\n", cmd.Args().Get(0), profile, cfg.ConfigPath)
+					return nil
+				},
+			},
+			{
+				Name:      "use-profile",
+				Usage:     "Make a profile the current one",
+				ArgsUsage: "<name>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 1 {
+						return usageErrorf("Usage: adowork config use-profile <name>")
+					}
+					if err := useProfile(cfg.ConfigPath, cmd.Args().First()); err != nil {
+						return err
+					}
+					fmt.Printf("Switched to profile %q\n", cmd.Args().First())
+					return nil
+				},
+			},
+			{
+				Name:  "validate",
+				Usage: "Check that the organization, project and credentials work",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if _, err := cfg.CheckMissing(); err != nil {
+						return err
+					}
+					client, err := adoclient.New(cfg, retryOption(cmd))
+					if err != nil {
+						return adoerrors.FormatADOError(err, "creating ADO client")
+					}
+					info, err := client.ValidateConnection(ctx)
+					if err != nil {
+						return err
+					}
+					fmt.Printf("OK: authenticated as %s; project %q (%s) is accessible in %s/%s\n",
+						valueOrNone(info.User), info.ProjectName, info.ProjectID, cfg.BaseURL, cfg.Organization)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// printConfig prints every resolved setting annotated with its source. The PAT and token are redacted.
+func printConfig(w io.Writer, cfg *config.Config) {
+	fmt.Fprintf(w, "Config file: %s\n", cfg.ConfigPath)
+	profile := valueOrNone(cfg.Profile)
+	if cfg.ProfileMissing() {
+		profile += " (not defined in the config file)"
+	}
+	fmt.Fprintf(w, "Profile: %s\n", profile)
+
+	rows := []struct{ key, value string }{
+		{config.KeyOrganization, cfg.Organization},
+		{config.KeyProject, cfg.Project},
+		{config.KeyBaseURL, cfg.BaseURL},
+		{config.KeyPAT, cfg.DescribeCredential(config.KeyPAT)},
+		{config.KeyToken, cfg.DescribeCredential(config.KeyToken)},
+		{"credential.env", cfg.Credential.Env},
+		{"credential.file", cfg.Credential.File},
+		{"credential.command", cfg.Credential.Command},
+		{"token.env", cfg.TokenSource.Env},
+		{"token.file", cfg.TokenSource.File},
+		{"token.command", cfg.TokenSource.Command},
+		{"defaults.type", cfg.Defaults.Type},
+		{"defaults.area", cfg.Defaults.Area},
+		{"defaults.iteration", cfg.Defaults.Iteration},
+		{"defaults.assigned-to", cfg.Defaults.AssignedTo},
+		{"defaults.description-format", cfg.Defaults.DescriptionFormat},
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, row := range rows {
+		source := cfg.Sources[row.key]
+		if source == "" && row.value != "" {
+			// Credential source and defaults can only come from the config file.
+			source = config.SourceFile
+		}
+		if row.value == "" {
+			source = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", row.key, valueOrNone(row.value), source)
+	}
+	tw.Flush()
+}
+
+// setConfigValue stores a value in a profile of the config file and returns the profile name.
+// The selected profile is used, or "default" if none is selected; it is created if needed.
+func setConfigValue(path, profileName, key, value string) (string, error) {
+	if !slices.Contains(config.ProfileKeys, key) {
+		return "", usageErrorf("Unknown config key: '%s'. Valid keys: %s", key, strings.Join(config.ProfileKeys, ", "))
+	}
+	if key == "defaults.description-format" {
+		if err := markup.CheckFormat(value); err != nil {
+			return "", err
+		}
+	}
+	file, err := config.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if profileName == "" {
+		profileName = config.DefaultProfile
+	}
+	if file.Profiles == nil {
+		file.Profiles = make(map[string]config.Profile)
+	}
+
+	profile := file.Profiles[profileName]
+	dst, _ := profile.Setting(key)
+	*dst = value
+	file.Profiles[profileName] = profile
+
+	// The first profile written becomes the current one, so it is used without --profile.
+	if file.CurrentProfile == "" {
+		file.CurrentProfile = profileName
+	}
+
+	return profileName, config.WriteFile(path, file)
+}
+
+// useProfile makes an existing profile the current one.
+func useProfile(path, profileName string) error {
+	file, err := config.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := file.Profiles[profileName]; !ok {
+		return fmt.Errorf("Profile %q not found in %s", profileName, path)
+	}
+	file.CurrentProfile = profileName
+	return config.WriteFile(path, file)
+}
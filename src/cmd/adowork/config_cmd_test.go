@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andreswebs/adowork/config"
+)
+
+func TestSetConfigValue_CreatesProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adowork", "config.yaml")
+
+	profile, err := setConfigValue(path, "", "organization", "my-org")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile != config.DefaultProfile {
+		t.Errorf("expected the default profile, got %q", profile)
+	}
+	if _, err := setConfigValue(path, "work", "defaults.type", "Bug"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	file, err := config.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if file.CurrentProfile != config.DefaultProfile {
+		t.Errorf("expected the first profile to become current, got %q", file.CurrentProfile)
+	}
+	if file.Profiles[config.DefaultProfile].Organization != "my-org" || file.Profiles["work"].Defaults.Type != "Bug" {
+		t.Errorf("unexpected profiles: %+v", file.Profiles)
+	}
+
+	if _, err := setConfigValue(path, "", "nope", "x"); err == nil {
+		t.Errorf("expected error for unknown key")
+	}
+}
+
+func TestUseProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if _, err := setConfigValue(path, "work", "project", "p"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := setConfigValue(path, "personal", "project", "q"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := useProfile(path, "personal"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	file, _ := config.ReadFile(path)
+	if file.CurrentProfile != "personal" {
+		t.Errorf("expected current profile 'personal', got %q", file.CurrentProfile)
+	}
+	if err := useProfile(path, "missing"); err == nil {
+		t.Errorf("expected error for unknown profile")
+	}
+}
+
+func TestPrintConfig_RedactsPATAndShowsSources(t *testing.T) {
+	cfg := &config.Config{
+		Organization: "org",
+		PAT:          "supersecrettoken1234",
+		BaseURL:      config.DefaultBaseURL,
+		Sources:      map[string]string{config.KeyOrganization: config.SourceFlag, config.KeyPAT: config.SourceEnv, config.KeyBaseURL: config.SourceDefault},
+		Defaults:     config.ProfileDefaults{Type: "Task"},
+	}
+	var buf bytes.Buffer
+	printConfig(&buf, cfg)
+	out := buf.String()
+
+	if strings.Contains(out, "supersecret") {
+		t.Errorf("expected the PAT to be redacted, got:\n%s", out)
+	}
+	for _, want := range []string{"****1234", "flag", "env", "default", "Task"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/andreswebs/adowork/config"
+	"github.com/andreswebs/adowork/markup"
+	"github.com/urfave/cli/v3"
+)
+
+// descriptionFlags returns the flags that give the description. usage describes --description.
+func descriptionFlags(usage string, local bool) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "description", Aliases: []string{"d"}, Usage: usage + " ('-' reads it from standard input)", Local: local},
+		&cli.StringFlag{Name: "description-file", Usage: "read the description from a file", Local: local},
+		descriptionFormatFlag(local),
+	}
+}
+
+// descriptionFormatFlag returns the flag selecting the format of the description and other HTML field values.
+func descriptionFormatFlag(local bool) cli.Flag {
+	return &cli.StringFlag{
+		Name:      "description-format",
+		Usage:     "format of the description and other HTML field values: markdown, html or text (default: the profile's defaults.description-format, or html)",
+		Local:     local,
+		Validator: markup.CheckFormat,
+	}
+}
+
+// descriptionFormat returns the format given with --description-format, or else the profile default.
+func descriptionFormat(cmd *cli.Command, defaults config.ProfileDefaults) string {
+	return stringFlagOrDefault(cmd, "description-format", defaults.DescriptionFormat)
+}
+
+// descriptionFromCommand returns the description given with --description or --description-file,
+// or nil if neither is set. "--description -" reads it from standard input. Trailing blank lines,
+// as left by editors and heredocs, are dropped.
+func descriptionFromCommand(cmd *cli.Command) (*string, error) {
+	if cmd.IsSet("description") && cmd.IsSet("description-file") {
+		return nil, usageErrorf("Use either --description or --description-file, not both.")
+	}
+
+	var data []byte
+	switch {
+	case cmd.IsSet("description-file"):
+		var err error
+		if data, err = os.ReadFile(cmd.String("description-file")); err != nil {
+			return nil, fmt.Errorf("Error reading description file: %w", err)
+		}
+	case cmd.String("description") == "-":
+		if cmd.Bool("pat-stdin") {
+			return nil, usageErrorf("--description - and --pat-stdin cannot both read standard input.")
+		}
+		var err error
+		if data, err = io.ReadAll(cmd.Root().Reader); err != nil {
+			return nil, fmt.Errorf("Error reading description from standard input: %w", err)
+		}
+	default:
+		return stringFlagPtr(cmd, "description"), nil
+	}
+
+	description := strings.TrimRight(string(data), " \t\r\n")
+	return &description, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v3"
+)
+
+func TestDescriptionFromCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "description.md")
+	if err := os.WriteFile(path, []byte("# Steps\n\n1. Save\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(stdin string, args ...string) (*string, error) {
+		var description *string
+		cmd := &cli.Command{
+			Reader: strings.NewReader(stdin),
+			Flags:  append([]cli.Flag{&cli.BoolFlag{Name: "pat-stdin"}}, descriptionFlags("description", false)...),
+			Action: func(ctx context.Context, cmd *cli.Command) error {
+				var err error
+				description, err = descriptionFromCommand(cmd)
+				return err
+			},
+		}
+		err := cmd.Run(context.Background(), append([]string{"adowork"}, args...))
+		return description, err
+	}
+
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  *string
+	}{
+		{"none", "", nil, nil},
+		{"flag", "", []string{"--description", "inline"}, stringPtr("inline")},
+		{"empty flag", "", []string{"--description", ""}, stringPtr("")},
+		{"file", "", []string{"--description-file", path}, stringPtr("# Steps\n\n1. Save")},
+		{"stdin", "piped\ntext\n", []string{"--description", "-"}, stringPtr("piped\ntext")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := run(tt.stdin, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"both", []string{"--description", "x", "--description-file", path}, "not both"},
+		{"missing file", []string{"--description-file", path + ".missing"}, "Error reading description file"},
+		{"stdin twice", []string{"--pat-stdin", "--description", "-"}, "cannot both read standard input"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := run("", tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
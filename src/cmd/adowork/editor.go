@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvVisual string = "VISUAL"
+	EnvEditor string = "EDITOR"
+
+	// defaultEditor is run when neither VISUAL nor EDITOR is set.
+	defaultEditor string = "vi"
+
+	frontMatterDelimiter string = "---"
+)
+
+// editorHelp is the comment at the top of the front matter of the file opened by --edit.
+const editorHelp = `# Edit the work item, then save the file and close the editor to create it.
+# The front matter takes the same keys as an import file: type, title, assigned-to, area,
+# iteration, parent (an ID), tags and fields. The description goes below the front matter, in
+# the format given by --description-format.
+# Leave the file unchanged to abort.
+`
+
+// editWorkItem opens the editor on a file holding the work item and returns the work item as
+// edited. The front matter holds the same attributes as a record of an import file; the body is
+// the description. It fails if the file is saved unchanged.
+func editWorkItem(record importRecord) (importRecord, error) {
+	template, err := editorTemplate(record)
+	if err != nil {
+		return importRecord{}, err
+	}
+	edited, err := runEditor(template)
+	if err != nil {
+		return importRecord{}, err
+	}
+	if bytes.Equal(edited, template) {
+		return importRecord{}, fmt.Errorf("Aborted: the work item file was left unchanged.")
+	}
+	return parseEditorFile(edited)
+}
+
+// editorTemplate renders the work item as front matter followed by the description. Every
+// attribute is listed, even when empty, so that it can be filled in.
+func editorTemplate(record importRecord) ([]byte, error) {
+	spec := record.Spec
+	var parent interface{} = ""
+	if spec.ParentID != nil {
+		parent = *spec.ParentID
+	}
+	tags := spec.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	fields := &yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range record.Fields {
+		if err := appendYAMLPair(fields, f.Name, f.Value); err != nil {
+			return nil, err
+		}
+	}
+	if len(fields.Content) == 0 {
+		fields.Style = yaml.FlowStyle
+	}
+
+	frontMatter := &yaml.Node{Kind: yaml.MappingNode}
+	for _, attr := range []struct {
+		key   string
+		value interface{}
+	}{
+		{"type", record.Type},
+		{"title", spec.Title},
+		{"assigned-to", spec.AssignedTo},
+		{"area", spec.Area},
+		{"iteration", spec.Iteration},
+		{"parent", parent},
+		{"tags", tags},
+		{"fields", fields},
+	} {
+		if err := appendYAMLPair(frontMatter, attr.key, attr.value); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelimiter + "\n" + editorHelp)
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(frontMatter); err != nil {
+		return nil, fmt.Errorf("Error preparing the work item file: %w", err)
+	}
+	buf.WriteString(frontMatterDelimiter + "\n")
+	if spec.Description != "" {
+		buf.WriteString(spec.Description + "\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// appendYAMLPair adds a key and its value to a mapping node.
+func appendYAMLPair(mapping *yaml.Node, key string, value interface{}) error {
+	valueNode, ok := value.(*yaml.Node)
+	if !ok {
+		valueNode = &yaml.Node{}
+		if err := valueNode.Encode(value); err != nil {
+			return fmt.Errorf("Error preparing the work item file: %w", err)
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, valueNode)
+	return nil
+}
+
+// parseEditorFile reads the work item back from the edited file.
+func parseEditorFile(data []byte) (importRecord, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	rest, ok := strings.CutPrefix(text, frontMatterDelimiter+"\n")
+	if !ok {
+		return importRecord{}, usageErrorf("Invalid work item file: it must start with a '%s' line.", frontMatterDelimiter)
+	}
+	frontMatter, body, ok := strings.Cut(rest, "\n"+frontMatterDelimiter+"\n")
+	if !ok {
+		if frontMatter, ok = strings.CutSuffix(rest, "\n"+frontMatterDelimiter); !ok {
+			return importRecord{}, usageErrorf("Invalid work item file: the front matter must end with a '%s' line.", frontMatterDelimiter)
+		}
+	}
+
+	record := importRecord{}
+	// Decoding from the opening delimiter keeps the line numbers of errors those of the file.
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(frontMatterDelimiter+"\n"+frontMatter), &node); err != nil {
+		return importRecord{}, usageErrorf("Invalid work item file: %w", err)
+	}
+	if len(node.Content) > 0 {
+		var err error
+		if record, err = readImportRecord(node.Content[0]); err != nil {
+			return importRecord{}, usageErrorf("Invalid work item file: %w", err)
+		}
+	}
+	if record.Key != "" || record.ParentKey != "" {
+		return importRecord{}, usageErrorf("Invalid work item file: the parent must be the ID of a work item.")
+	}
+	record.Spec.Description = strings.TrimRight(body, " \t\r\n")
+
+	if record.Type == "" {
+		return importRecord{}, usageErrorf("Invalid work item file: missing type.")
+	}
+	if record.Spec.Title == "" {
+		return importRecord{}, usageErrorf("Invalid work item file: missing title.")
+	}
+	return record, nil
+}
+
+// runEditor writes content to a temporary file, opens it in the user's editor and returns what was
+// saved. The editor is run through the shell, so that VISUAL or EDITOR may include arguments,
+// such as "code --wait".
+func runEditor(content []byte) ([]byte, error) {
+	f, err := os.CreateTemp("", "adowork-*.md")
+	if err != nil {
+		return nil, fmt.Errorf("Error creating the work item file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error writing the work item file: %w", err)
+	}
+
+	editor := editorCommand()
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("Editor '%s' failed: %w", editor, err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading the work item file: %w", err)
+	}
+	return edited, nil
+}
+
+// editorCommand returns the editor to run: VISUAL, then EDITOR, then vi.
+func editorCommand() string {
+	for _, env := range []string{EnvVisual, EnvEditor} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	return defaultEditor
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andreswebs/adowork/config"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/urfave/cli/v3"
+)
+
+func TestEditorTemplate_RoundTrip(t *testing.T) {
+	parent := 12
+	record := importRecord{
+		Type: "Bug",
+		Spec: workItemSpec{
+			Title:       "Crash: on save",
+			Description: "Steps:\n\n---\n\n1. Save",
+			Area:        `proj\Team A`,
+			ParentID:    &parent,
+			Tags:        []string{"ui"},
+		},
+		Fields: []fieldAssignment{{Name: "Priority", Value: "2"}, {Name: "Custom.Notes", Value: "a: b"}},
+	}
+	template, err := editorTemplate(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(template), "---\n# Edit the work item") || !strings.Contains(string(template), "\nparent: 12\n") {
+		t.Errorf("unexpected template:\n%s", template)
+	}
+
+	got, err := parseEditorFile(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Line = 0
+	if !reflect.DeepEqual(got, record) {
+		t.Errorf("expected the work item back from its template, got %+v", got)
+	}
+}
+
+func TestParseEditorFile_Errors(t *testing.T) {
+	tests := []struct {
+		name, file, want string
+	}{
+		{"no front matter", "title: x\n", "must start with a '---' line"},
+		{"unterminated", "---\ntype: Bug\ntitle: x\n", "must end with a '---' line"},
+		{"missing title", "---\ntype: Bug\ntitle: \"\"\n---\n", "missing title"},
+		{"missing type", "---\ntitle: x\n---\n", "missing type"},
+		{"parent key", "---\ntype: Bug\ntitle: x\nparent: epic\n---\n", "the parent must be the ID of a work item"},
+		{"unknown key", "---\ntype: Bug\ntitle: x\nstate: New\n---\n", "line 4: unknown attribute 'state'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseEditorFile([]byte(tt.file))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// The closing delimiter may end the file.
+	if record, err := parseEditorFile([]byte("---\ntype: Bug\ntitle: x\n---")); err != nil || record.Spec.Title != "x" {
+		t.Errorf("expected the work item, got %+v, %v", record, err)
+	}
+}
+
+func TestAction_Edit(t *testing.T) {
+	client := newFakeClient()
+	run := func(args ...string) error {
+		cmd := &cli.Command{
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{Name: "type"},
+				&cli.StringFlag{Name: "title"},
+				&cli.StringFlag{Name: "assigned-to"},
+				&cli.StringFlag{Name: "area"},
+				&cli.StringFlag{Name: "iteration"},
+				&cli.IntFlag{Name: "parent"},
+				&cli.BoolFlag{Name: "edit"},
+				&cli.BoolFlag{Name: "dry-run"},
+			}, descriptionFlags("description", false)...),
+			Action: func(ctx context.Context, cmd *cli.Command) error {
+				return actionWithClient(ctx, cmd, client, config.ProfileDefaults{Type: "Bug"})
+			},
+		}
+		return cmd.Run(context.Background(), append([]string{"adowork", "--edit"}, args...))
+	}
+
+	// The "editor" fills in the title and appends a line to the description.
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	script := "#!/bin/sh\nsed 's/^title: .*/title: Edited/' \"$1\" > \"$1.new\" && echo more >> \"$1.new\" && mv \"$1.new\" \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvVisual, editor)
+	t.Setenv(EnvEditor, "false")
+	if err := run("--description", "first", "--description-format", "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	fields := workItemFields(t, client, 1)
+	if got := fields[patch.FieldTitle]; got != "Edited" {
+		t.Errorf("expected the edited title, got %v", got)
+	}
+	if got := fields[patch.FieldDescription]; got != "<p>first<br>\nmore</p>" {
+		t.Errorf("expected the edited description as HTML, got %v", got)
+	}
+
+	// An editor that saves the file unchanged aborts the create.
+	t.Setenv(EnvVisual, "true")
+	if err := run("--title", "Untouched"); err == nil || !strings.Contains(err.Error(), "left unchanged") {
+		t.Errorf("expected the create to be aborted, got %v", err)
+	}
+	if _, err := client.GetWorkItem(context.Background(), 2); err == nil {
+		t.Error("expected nothing to be created")
+	}
+
+	t.Setenv(EnvVisual, "")
+	if err := run("--title", "x"); err == nil || !strings.Contains(err.Error(), "Editor 'false' failed") {
+		t.Errorf("expected EDITOR to be used when VISUAL is empty, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/urfave/cli/v3"
+)
+
+// exitCode returns the exit code of the error class, checking the classes in order of precedence.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return adoerrors.ExitOK
+	case adoerrors.IsConfigError(err):
+		return adoerrors.ExitConfig
+	case adoerrors.IsUsageError(err):
+		return adoerrors.ExitUsage
+	case adoerrors.IsAuthError(err):
+		return adoerrors.ExitAuth
+	case adoerrors.IsNetworkError(err):
+		return adoerrors.ExitNetwork
+	case adoerrors.IsValidationError(err):
+		return adoerrors.ExitValidation
+	case adoerrors.IsRateLimitError(err):
+		return adoerrors.ExitRateLimit
+	case adoerrors.IsConflictError(err):
+		return adoerrors.ExitConflict
+	case adoerrors.IsMalformedResponseError(err):
+		return adoerrors.ExitMalformedResponse
+	default:
+		return adoerrors.ExitError
+	}
+}
+
+// EnvErrorFormat selects the format of error output: text (the default) or json.
+const EnvErrorFormat string = "ADOWORK_ERROR_FORMAT"
+
+// errorClass describes how errors of one class are reported.
+type errorClass struct {
+	// Name identifies the class in JSON error output.
+	Name       string
+	Summary    string
+	Suggestion string
+	// ShowDetails prints the error itself after the summary in text output.
+	ShowDetails bool
+}
+
+// errorClasses maps each exit code to the way its errors are reported.
+var errorClasses = map[int]errorClass{
+	adoerrors.ExitError: {"unexpected", "An unexpected error occurred.",
+		"Retry the operation or contact support if the issue continues.", true},
+	adoerrors.ExitConfig: {"config", "Configuration error. Required settings are missing or invalid.",
+		"Run 'adowork config view' to see the resolved settings and where each comes from.", true},
+	adoerrors.ExitValidation: {"validation", "Validation error. One or more input parameters are invalid.",
+		"Review your command-line arguments and environment variables for missing or incorrect values.", true},
+	adoerrors.ExitAuth: {"auth", "Authentication failed. Unable to access Azure DevOps with the provided credentials.",
+		"Check your Personal Access Token (PAT) or access token for validity, permissions, and expiration. Ensure it is set in the ADO_PAT or ADO_TOKEN environment variable.", false},
+	adoerrors.ExitNetwork: {"network", "Network error. Unable to connect to Azure DevOps services.",
+		"Check your internet connection and verify Azure DevOps is reachable. Retry after a few moments.", false},
+	adoerrors.ExitRateLimit: {"rate-limit", "Rate limit exceeded. Too many requests sent to Azure DevOps.",
+		"Wait a few minutes before retrying. Consider reducing request frequency or checking your organization's API quota.", false},
+	adoerrors.ExitConflict: {"conflict", "Conflict. The work item was modified by someone else since the expected revision.",
+		"Fetch the latest revision of the work item and retry the update.", true},
+	adoerrors.ExitMalformedResponse: {"malformed-response", "Malformed response. Received unexpected or invalid data from Azure DevOps.",
+		"Retry the operation. If the problem persists, check for Azure DevOps service issues or API changes.", false},
+	adoerrors.ExitUsage: {"usage", "Usage error. The command-line input is invalid.",
+		"Run the command with --help to see its flags and arguments.", true},
+}
+
+// usageErrorf formats an error about invalid command-line input, which exits with ExitUsage.
+func usageErrorf(format string, a ...interface{}) error {
+	return &adoerrors.UsageError{Err: fmt.Errorf(format, a...)}
+}
+
+// onUsageError marks the errors urfave/cli reports while parsing flags, including those of flag
+// validators, as usage errors instead of printing the help text.
+func onUsageError(ctx context.Context, cmd *cli.Command, err error, isSubcommand bool) error {
+	return &adoerrors.UsageError{Err: err}
+}
+
+// setOnUsageError installs onUsageError on cmd and all of its subcommands.
+func setOnUsageError(cmd *cli.Command) {
+	cmd.OnUsageError = onUsageError
+	for _, sub := range cmd.Commands {
+		setOnUsageError(sub)
+	}
+}
+
+// errorReport is the JSON form of an error. Fields that do not apply to the error are omitted,
+// except class, exitCode, message and suggestion.
+type errorReport struct {
+	Class            string                 `json:"class"`
+	ExitCode         int                    `json:"exitCode"`
+	Message          string                 `json:"message"`
+	Suggestion       string                 `json:"suggestion"`
+	HTTPStatus       int                    `json:"httpStatus,omitempty"`
+	Operation        string                 `json:"operation,omitempty"`
+	TypeKey          string                 `json:"typeKey,omitempty"`
+	ErrorCode        int                    `json:"errorCode,omitempty"`
+	CustomProperties map[string]interface{} `json:"customProperties,omitempty"`
+	Fields           []adoerrors.FieldError `json:"fields,omitempty"`
+}
+
+// newErrorReport collects the details of an error for JSON output.
+func newErrorReport(err error) errorReport {
+	code := exitCode(err)
+	class := errorClasses[code]
+	report := errorReport{Class: class.Name, ExitCode: code, Message: err.Error(), Suggestion: class.Suggestion}
+
+	var opErr *adoerrors.OperationError
+	if errors.As(err, &opErr) {
+		report.Operation = opErr.Operation
+	}
+	var fieldErr *adoerrors.FieldValidationError
+	if errors.As(err, &fieldErr) {
+		report.Fields = fieldErr.Fields
+	}
+	if we := adoerrors.APIError(err); we != nil {
+		if we.StatusCode != nil {
+			report.HTTPStatus = *we.StatusCode
+		}
+		if we.TypeKey != nil {
+			report.TypeKey = *we.TypeKey
+		}
+		if we.ErrorCode != nil {
+			report.ErrorCode = *we.ErrorCode
+		}
+		if we.CustomProperties != nil {
+			report.CustomProperties = *we.CustomProperties
+		}
+	}
+	return report
+}
+
+// reportError writes the error to w and returns the exit code of its class. It prints a
+// user-friendly message and suggestion, or a single JSON object when format is json, and
+// technical details if DEBUG is set.
+func reportError(w io.Writer, err error, format string) int {
+	code := exitCode(err)
+	if err == nil {
+		return code
+	}
+
+	if format == outputJSON {
+		// One line, so that log parsers can read each error on its own.
+		report, _ := json.Marshal(newErrorReport(err))
+		fmt.Fprintln(w, string(report))
+		return code
+	}
+
+	class := errorClasses[code]
+	fmt.Fprintln(w, "Error: "+class.Summary)
+	fmt.Fprintln(w, "Suggestion: "+class.Suggestion)
+	if class.ShowDetails {
+		fmt.Fprintf(w, "Details: %v\n", err)
+	}
+	var fieldErr *adoerrors.FieldValidationError
+	if errors.As(err, &fieldErr) {
+		fmt.Fprintln(w, "Rejected fields:")
+		for _, f := range fieldErr.Fields {
+			fmt.Fprintln(w, "  - "+f.String())
+		}
+	}
+
+	// If DEBUG is set, print technical details
+	if os.Getenv("DEBUG") != "" {
+		fmt.Fprintln(w, "--- Technical details ---")
+		fmt.Fprintf(w, "%+v\n", err)
+	}
+	return code
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/andreswebs/adowork/config"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/urfave/cli/v3"
+)
+
+func makeWrappedError(status int) error {
+	code := status
+	msg := "mock error"
+	return azuredevops.WrappedError{StatusCode: &code, Message: &msg}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, adoerrors.ExitOK},
+		{"missing config", &adoerrors.ConfigError{Err: errors.New("Missing required configuration")}, adoerrors.ExitConfig},
+		{"wrapped config", adoerrors.FormatADOError(&adoerrors.ConfigError{Err: errors.New("bad file")}, "loading config"), adoerrors.ExitConfig},
+		{"auth", adoerrors.FormatADOError(makeWrappedError(401), "creating work item"), adoerrors.ExitAuth},
+		{"network", &net.DNSError{Err: "no such host", Name: "dev.azure.com"}, adoerrors.ExitNetwork},
+		{"validation", makeWrappedError(400), adoerrors.ExitValidation},
+		{"rate limit", makeWrappedError(429), adoerrors.ExitRateLimit},
+		{"conflict", makeWrappedError(412), adoerrors.ExitConflict},
+		{"malformed", &json.SyntaxError{}, adoerrors.ExitMalformedResponse},
+		{"usage", adoerrors.FormatADOError(usageErrorf("Invalid field: 'x'."), "building work item patch document"), adoerrors.ExitUsage},
+		{"other", errors.New("boom"), adoerrors.ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnUsageError(t *testing.T) {
+	var cfg config.Config
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"validator", []string{"adowork", "--description-format", "rtf"}},
+		{"subcommand validator", []string{"adowork", "import", "--concurrency", "0", "--file", "items.yaml"}},
+		{"unknown flag", []string{"adowork", "--colour"}},
+		{"bad flag value", []string{"adowork", "--parent", "epic"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cli.Command{
+				Name:     "adowork",
+				Flags:    []cli.Flag{descriptionFormatFlag(true), &cli.IntFlag{Name: "parent"}},
+				Commands: []*cli.Command{importCommand(&cfg)},
+				Action:   func(ctx context.Context, cmd *cli.Command) error { return nil },
+			}
+			setOnUsageError(cmd)
+			err := cmd.Run(context.Background(), tt.args)
+			if got := exitCode(err); got != adoerrors.ExitUsage {
+				t.Errorf("expected exit code %d, got %d (%v)", adoerrors.ExitUsage, got, err)
+			}
+		})
+	}
+}
+
+func TestNewErrorReport(t *testing.T) {
+	status, errorCode := 400, 600171
+	message, typeKey := "TF401320: Rule Error for field Priority.", "RuleValidationException"
+	properties := map[string]interface{}{"FieldReferenceName": "Microsoft.VSTS.Common.Priority"}
+	apiErr := azuredevops.WrappedError{StatusCode: &status, Message: &message, TypeKey: &typeKey, ErrorCode: &errorCode, CustomProperties: &properties}
+
+	report := newErrorReport(adoerrors.FormatADOError(&apiErr, "creating work item"))
+	if report.Class != "validation" || report.ExitCode != adoerrors.ExitValidation {
+		t.Errorf("expected a validation error, got %s (%d)", report.Class, report.ExitCode)
+	}
+	if report.Operation != "creating work item" || report.HTTPStatus != 400 {
+		t.Errorf("expected the operation and status, got %q and %d", report.Operation, report.HTTPStatus)
+	}
+	if report.TypeKey != typeKey || report.ErrorCode != errorCode || report.CustomProperties["FieldReferenceName"] != "Microsoft.VSTS.Common.Priority" {
+		t.Errorf("expected the Azure DevOps error details, got %+v", report)
+	}
+	if report.Message != "creating work item failed (HTTP 400): "+message || report.Suggestion == "" {
+		t.Errorf("expected the message and a suggestion, got %+v", report)
+	}
+
+	data, err := json.Marshal(newErrorReport(errors.New("boom")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"class":"unexpected","exitCode":1,"message":"boom","suggestion":"Retry the operation or contact support if the issue continues."}` {
+		t.Errorf("expected the fields that do not apply to be omitted, got %s", data)
+	}
+}
+
+func TestReportError(t *testing.T) {
+	var out strings.Builder
+	code := reportError(&out, adoerrors.FormatADOError(makeWrappedError(401), "creating work item"), outputText)
+	if code != adoerrors.ExitAuth {
+		t.Errorf("expected exit code %d, got %d", adoerrors.ExitAuth, code)
+	}
+	if !strings.HasPrefix(out.String(), "Error: Authentication failed.") || !strings.Contains(out.String(), "\nSuggestion: ") {
+		t.Errorf("expected the error and suggestion lines, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	code = reportError(&out, errors.New("boom"), outputJSON)
+	if code != adoerrors.ExitError {
+		t.Errorf("expected exit code %d, got %d", adoerrors.ExitError, code)
+	}
+	var report errorReport
+	if err := json.Unmarshal([]byte(out.String()), &report); err != nil || strings.Count(out.String(), "\n") != 1 {
+		t.Fatalf("expected a single line of JSON, got %q (%v)", out.String(), err)
+	}
+	if report.Class != "unexpected" || report.Message != "boom" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestErrorFormatFromCommand(t *testing.T) {
+	run := func(args ...string) string {
+		cmd := &cli.Command{
+			Flags: []cli.Flag{outputFlag()},
+			Commands: []*cli.Command{{
+				Name:   "show",
+				Action: func(ctx context.Context, cmd *cli.Command) error { return nil },
+			}},
+		}
+		if err := cmd.Run(context.Background(), append([]string{"adowork"}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		return errorFormatFromCommand(cmd)
+	}
+
+	t.Setenv(EnvErrorFormat, "")
+	if got := run("show"); got != "" {
+		t.Errorf("expected no format by default, got %q", got)
+	}
+	if got := run("show", "-o", "json"); got != outputJSON {
+		t.Errorf("expected --output on a subcommand to select JSON, got %q", got)
+	}
+	t.Setenv(EnvErrorFormat, outputJSON)
+	if got := run("show"); got != outputJSON {
+		t.Errorf("expected %s to select JSON, got %q", EnvErrorFormat, got)
+	}
+	if got := run("--output", "text", "show"); got != outputText {
+		t.Errorf("expected --output text to override %s, got %q", EnvErrorFormat, got)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+// explainFieldErrors adds the rejected fields to a validation error: their reference and display
+// names, the values sent in patchDoc and, when the work item type is known, the allowed values.
+// Other errors are returned unchanged.
+func explainFieldErrors(ctx context.Context, client adoclient.ClientV1, workItemType string, patchDoc []webapi.JsonPatchOperation, err error) error {
+	if err == nil || !adoerrors.IsValidationError(err) {
+		return err
+	}
+	fields := adoerrors.ParseFieldErrors(err)
+	if len(fields) == 0 {
+		return err
+	}
+
+	var defs []workitemtracking.WorkItemTypeFieldWithReferences
+	if workItemType != "" {
+		// Allowed values only enrich the report, so a failure to fetch them is ignored.
+		defs, _ = client.GetWorkItemTypeFields(ctx, workItemType)
+	}
+	for i := range fields {
+		f := &fields[i]
+		if def, ok := findTypeField(defs, f.Field); ok {
+			f.Field, f.Name = *def.ReferenceName, stringValue(def.Name)
+			if def.AllowedValues != nil {
+				for _, v := range *def.AllowedValues {
+					f.AllowedValues = append(f.AllowedValues, fmt.Sprint(v))
+				}
+			}
+		}
+		if f.Value == nil {
+			f.Value = patch.FieldValue(patchDoc, f.Field)
+		}
+	}
+	return &adoerrors.FieldValidationError{Fields: fields, Err: err}
+}
+
+// workItemTypeOf returns the type of an existing work item, or "" if it cannot be fetched.
+func workItemTypeOf(ctx context.Context, client adoclient.ClientV1, workItemID int) string {
+	workItem, err := client.GetWorkItem(ctx, workItemID)
+	if err != nil || workItem == nil || workItem.Fields == nil {
+		return ""
+	}
+	workItemType, _ := (*workItem.Fields)["System.WorkItemType"].(string)
+	return workItemType
+}
+
+// findTypeField returns the field of a work item type with the given reference or display name.
+func findTypeField(defs []workitemtracking.WorkItemTypeFieldWithReferences, name string) (workitemtracking.WorkItemTypeFieldWithReferences, bool) {
+	for _, def := range defs {
+		if def.ReferenceName != nil && (strings.EqualFold(*def.ReferenceName, name) || (def.Name != nil && strings.EqualFold(*def.Name, name))) {
+			return def, true
+		}
+	}
+	return workitemtracking.WorkItemTypeFieldWithReferences{}, false
+}
+
+// stringValue returns the string, or "" for nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/andreswebs/adowork/client/fake"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+func makeValidationError(message string, properties map[string]interface{}) *azuredevops.WrappedError {
+	status := 400
+	err := &azuredevops.WrappedError{StatusCode: &status, Message: &message}
+	if properties != nil {
+		err.CustomProperties = &properties
+	}
+	return err
+}
+
+func TestParseFieldErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []adoerrors.FieldError
+	}{
+		{
+			"rule error message",
+			makeValidationError("TF401320: Rule Error for field Priority. Error code: Required, HasValues, LimitedToValues, AllowsOldValue, InvalidEmpty.", nil),
+			[]adoerrors.FieldError{{Field: "Priority", Reason: "a value is required; the value is not one of the allowed values"}},
+		},
+		{
+			"field status message",
+			makeValidationError("TF401326: Invalid field status 'InvalidListValue' for field 'Microsoft.VSTS.Common.Severity'.", nil),
+			[]adoerrors.FieldError{{Field: "Microsoft.VSTS.Common.Severity", Reason: "the value is not one of the allowed values"}},
+		},
+		{
+			"unsupported value message",
+			makeValidationError("The field 'State' contains the value 'Doing' that is not in the list of supported values", nil),
+			[]adoerrors.FieldError{{Field: "State", Value: "Doing", Reason: "the value is not one of the allowed values"}},
+		},
+		{
+			"custom properties",
+			makeValidationError("TF401320: Rule Error for field Priority.", map[string]interface{}{
+				"FieldReferenceName": "Microsoft.VSTS.Common.Priority",
+				"FieldStatusFlags":   "required, invalidEmpty",
+				"InvalidValue":       "",
+			}),
+			[]adoerrors.FieldError{{Field: "Microsoft.VSTS.Common.Priority", Value: "", Reason: "a value is required"}},
+		},
+		{
+			"rule validation errors",
+			makeValidationError("TF401347: Invalid tree name given for work item -1, field 'System.AreaPath'.", map[string]interface{}{
+				"RuleValidationErrors": []interface{}{
+					map[string]interface{}{"fieldReferenceName": "System.AreaPath", "fieldStatusFlags": "none", "errorMessage": "Invalid tree name given"},
+					map[string]interface{}{"fieldReferenceName": "System.Title", "fieldStatusFlags": "required, invalidEmpty"},
+				},
+			}),
+			[]adoerrors.FieldError{
+				{Field: "System.AreaPath", Reason: "Invalid tree name given"},
+				{Field: "System.Title", Reason: "a value is required"},
+			},
+		},
+		{"not an API error", errors.New("boom"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := adoerrors.ParseFieldErrors(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("adoerrors.ParseFieldErrors() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplainFieldErrors(t *testing.T) {
+	ref, name := "Microsoft.VSTS.Common.Priority", "Priority"
+	allowed := []interface{}{1.0, 2.0, 3.0, 4.0}
+	// Only Bug has metadata, so the allowed values come from the type of the work item.
+	client := &fake.Client{TypeFields: map[string][]workitemtracking.WorkItemTypeFieldWithReferences{
+		"Bug": {{ReferenceName: &ref, Name: &name, AllowedValues: &allowed}},
+	}}
+	patchDoc := []webapi.JsonPatchOperation{
+		patch.NewOperation(webapi.OperationValues.Add, "/fields/System.Title", "Crash"),
+		patch.NewOperation(webapi.OperationValues.Add, "/fields/Microsoft.VSTS.Common.Priority", 7),
+	}
+	apiErr := makeValidationError("TF401320: Rule Error for field Priority. Error code: InvalidListValue.", nil)
+
+	err := explainFieldErrors(context.Background(), client, "Bug", patchDoc, apiErr)
+	var fieldErr *adoerrors.FieldValidationError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("expected a field validation error, got %v", err)
+	}
+	want := adoerrors.FieldError{Field: ref, Name: name, Value: 7, Reason: "the value is not one of the allowed values", AllowedValues: []string{"1", "2", "3", "4"}}
+	if len(fieldErr.Fields) != 1 || !reflect.DeepEqual(fieldErr.Fields[0], want) {
+		t.Errorf("expected %+v, got %+v", want, fieldErr.Fields)
+	}
+	if got := fieldErr.Fields[0].String(); got != "Priority (Microsoft.VSTS.Common.Priority): the value is not one of the allowed values. Value: '7'. Allowed values: 1, 2, 3, 4." {
+		t.Errorf("unexpected rendering: %s", got)
+	}
+	if exitCode(adoerrors.FormatADOError(err, "creating work item")) != adoerrors.ExitValidation || err.Error() != apiErr.Error() {
+		t.Error("expected the error to keep its class and message")
+	}
+
+	// Without metadata, the field is still reported with the value sent.
+	client.Errors = map[string]error{"GetWorkItemTypeFields": errors.New("metadata unavailable")}
+	err = explainFieldErrors(context.Background(), client, "Bug", patchDoc, makeValidationError("TF401326: Invalid field status 'InvalidListValue' for field 'Microsoft.VSTS.Common.Priority'.", nil))
+	if !errors.As(err, &fieldErr) || fieldErr.Fields[0].Value != 7 || fieldErr.Fields[0].AllowedValues != nil {
+		t.Errorf("expected the sent value without allowed values, got %v", err)
+	}
+
+	if other := errors.New("boom"); explainFieldErrors(context.Background(), client, "Bug", patchDoc, other) != other {
+		t.Error("expected other errors to be returned unchanged")
+	}
+}
+
+func TestImportError_ListsRejectedFields(t *testing.T) {
+	fieldErr := &adoerrors.FieldValidationError{
+		Fields: []adoerrors.FieldError{{Field: "System.Title", Reason: "a value is required"}},
+		Err:    makeValidationError("TF401320: Rule Error for field Title.", nil),
+	}
+	results := []importResult{{Item: importItem{Record: importRecord{Line: 3}}, Err: adoerrors.FormatADOError(fieldErr, "creating work item")}}
+	want := "1 of 1 work items were not created:\n  - line 3: creating work item failed (HTTP 400): TF401320: Rule Error for field Title.\n      System.Title: a value is required."
+	if err := importError(results); err == nil || err.Error() != want {
+		t.Errorf("expected %q, got %v", want, err)
+	}
+}
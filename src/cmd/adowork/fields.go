@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/markup"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// fieldAssignment is a raw field value given with --field or in a fields file.
+// Name is either a field reference name (e.g. Microsoft.VSTS.Common.Priority) or its display name.
+type fieldAssignment struct {
+	Name  string
+	Value interface{}
+}
+
+// fieldFlags returns the flags used to set arbitrary work item fields.
+func fieldFlags(local bool) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{Name: "field", Aliases: []string{"f"}, Usage: "set a field as Name=Value (repeatable)", Local: local},
+		&cli.StringFlag{Name: "fields-file", Usage: "JSON or YAML file mapping field names to values", Local: local},
+	}
+}
+
+// fieldAssignmentsFromCommand returns the fields given with --fields-file and --field, in that order,
+// so that values from --field take precedence over the fields file.
+func fieldAssignmentsFromCommand(cmd *cli.Command) ([]fieldAssignment, error) {
+	var assignments []fieldAssignment
+	if path := cmd.String("fields-file"); path != "" {
+		fromFile, err := readFieldsFile(path)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, fromFile...)
+	}
+	fromFlags, err := parseFieldFlags(cmd.StringSlice("field"))
+	if err != nil {
+		return nil, err
+	}
+	return append(assignments, fromFlags...), nil
+}
+
+// fieldPatchOperations returns the patch operations for the given fields. When allowRemove is true,
+// an empty value removes the field instead of being rejected. Values of HTML fields are converted
+// from format.
+func fieldPatchOperations(ctx context.Context, client adoclient.ClientV1, assignments []fieldAssignment, allowRemove bool, format string) ([]webapi.JsonPatchOperation, error) {
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+
+	defs, err := client.GetFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildFieldOperations(assignments, defs, allowRemove, format)
+}
+
+// parseFieldFlags parses Name=Value pairs. Only the first '=' separates the name from the value.
+func parseFieldFlags(values []string) ([]fieldAssignment, error) {
+	assignments := make([]fieldAssignment, 0, len(values))
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, usageErrorf("Invalid field: '%s'. Use the form Name=Value.", v)
+		}
+		assignments = append(assignments, fieldAssignment{Name: name, Value: value})
+	}
+	return assignments, nil
+}
+
+// readFieldsFile reads a JSON or YAML object mapping field names to values.
+// YAML is a superset of JSON, so a single decoder handles both formats.
+func readFieldsFile(path string) ([]fieldAssignment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading fields file: %w", err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, usageErrorf("Error parsing fields file '%s': %w", path, err)
+	}
+	if len(node.Content) == 0 {
+		return nil, nil
+	}
+	doc := node.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil, usageErrorf("Error parsing fields file '%s': expected an object mapping field names to values", path)
+	}
+	return mappingFieldAssignments(doc, func(key *yaml.Node, err error) error {
+		return usageErrorf("Error parsing field '%s' in '%s': %w", key.Value, path, err)
+	})
+}
+
+// mappingFieldAssignments turns a YAML mapping of field names to values into assignments, in the
+// mapping's key order. valueError builds the error for a value that cannot be decoded.
+func mappingFieldAssignments(node *yaml.Node, valueError func(key *yaml.Node, err error) error) ([]fieldAssignment, error) {
+	// Walk the mapping node directly to keep the key order.
+	var assignments []fieldAssignment
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var value interface{}
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return nil, valueError(node.Content[i], err)
+		}
+		assignments = append(assignments, fieldAssignment{Name: node.Content[i].Value, Value: value})
+	}
+	return assignments, nil
+}
+
+// buildFieldOperations resolves field names against the field definitions and builds one
+// operation per field, coercing each value to the field's type. A later assignment of the
+// same field replaces an earlier one. Values of HTML fields are converted from format.
+func buildFieldOperations(assignments []fieldAssignment, defs []workitemtracking.WorkItemField, allowRemove bool, format string) ([]webapi.JsonPatchOperation, error) {
+	var order []string
+	values := make(map[string]interface{})
+	removed := make(map[string]bool)
+
+	for _, a := range assignments {
+		def, ok := findField(defs, a.Name)
+		if !ok {
+			return nil, usageErrorf("Unknown field: '%s'. Use a field reference name such as 'Microsoft.VSTS.Common.Priority'.", a.Name)
+		}
+		ref := *def.ReferenceName
+		if def.ReadOnly != nil && *def.ReadOnly {
+			return nil, usageErrorf("Field '%s' is read-only and cannot be set.", ref)
+		}
+		if _, seen := values[ref]; !seen && !removed[ref] {
+			order = append(order, ref)
+		}
+
+		if isEmptyFieldValue(a.Value) {
+			if !allowRemove {
+				return nil, usageErrorf("Field '%s' has an empty value.", ref)
+			}
+			removed[ref] = true
+			delete(values, ref)
+			continue
+		}
+
+		value, err := coerceFieldValue(def, a.Value, format)
+		if err != nil {
+			return nil, err
+		}
+		delete(removed, ref)
+		values[ref] = value
+	}
+
+	b := patch.New()
+	for _, ref := range order {
+		if removed[ref] {
+			b.RemoveField(ref)
+			continue
+		}
+		b.Field(ref, values[ref])
+	}
+	return b.Build()
+}
+
+// findField looks up a field definition by reference name or display name, case-insensitively.
+func findField(defs []workitemtracking.WorkItemField, name string) (workitemtracking.WorkItemField, bool) {
+	for _, def := range defs {
+		if def.ReferenceName != nil && strings.EqualFold(*def.ReferenceName, name) {
+			return def, true
+		}
+	}
+	for _, def := range defs {
+		if def.ReferenceName != nil && def.Name != nil && strings.EqualFold(*def.Name, name) {
+			return def, true
+		}
+	}
+	return workitemtracking.WorkItemField{}, false
+}
+
+// isEmptyFieldValue reports whether a value is missing or an empty string.
+func isEmptyFieldValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && strings.TrimSpace(s) == ""
+}
+
+// coerceFieldValue converts a raw value to the JSON type expected by the field. Text for an HTML
+// field is converted to HTML from format.
+func coerceFieldValue(def workitemtracking.WorkItemField, v interface{}, format string) (interface{}, error) {
+	ref := *def.ReferenceName
+	raw := rawFieldString(v)
+
+	var fieldType workitemtracking.FieldType
+	if def.Type != nil {
+		fieldType = *def.Type
+	}
+
+	invalid := func(kind string) error {
+		return usageErrorf("Invalid value for field '%s': '%s' is not a valid %s.", ref, raw, kind)
+	}
+
+	switch fieldType {
+	case workitemtracking.FieldTypeValues.Integer, workitemtracking.FieldTypeValues.PicklistInteger:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, invalid("integer")
+		}
+		return n, nil
+	case workitemtracking.FieldTypeValues.Double, workitemtracking.FieldTypeValues.PicklistDouble:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, invalid("number")
+		}
+		return f, nil
+	case workitemtracking.FieldTypeValues.Boolean:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, invalid("boolean")
+		}
+		return b, nil
+	case workitemtracking.FieldTypeValues.DateTime:
+		t, err := parseDateTime(raw)
+		if err != nil {
+			return nil, invalid("date (use YYYY-MM-DD or RFC 3339)")
+		}
+		return t.UTC().Format(time.RFC3339), nil
+	case workitemtracking.FieldTypeValues.Html:
+		return markup.ToHTML(raw, format)
+	default:
+		// Strings, identities and tree paths are sent as text.
+		return raw, nil
+	}
+}
+
+// rawFieldString renders a decoded value as the text the user wrote.
+func rawFieldString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case time.Time:
+		return t.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// parseDateTime accepts RFC 3339 timestamps and plain dates.
+func parseDateTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, s)
+}
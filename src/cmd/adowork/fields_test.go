@@ -0,0 +1,134 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+func makeFieldDef(ref, name string, fieldType workitemtracking.FieldType) workitemtracking.WorkItemField {
+	return workitemtracking.WorkItemField{ReferenceName: &ref, Name: &name, Type: &fieldType}
+}
+
+var testFieldDefs = []workitemtracking.WorkItemField{
+	makeFieldDef("Microsoft.VSTS.Common.Priority", "Priority", workitemtracking.FieldTypeValues.Integer),
+	makeFieldDef("Microsoft.VSTS.Scheduling.StoryPoints", "Story Points", workitemtracking.FieldTypeValues.Double),
+	makeFieldDef("Custom.Blocked", "Blocked", workitemtracking.FieldTypeValues.Boolean),
+	makeFieldDef("Microsoft.VSTS.Scheduling.DueDate", "Due Date", workitemtracking.FieldTypeValues.DateTime),
+	makeFieldDef("Custom.Notes", "Notes", workitemtracking.FieldTypeValues.Html),
+}
+
+func TestParseFieldFlags(t *testing.T) {
+	got, err := parseFieldFlags([]string{"Priority=2", "Custom.Notes=a=b, c"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "Priority" || got[0].Value != "2" || got[1].Value != "a=b, c" {
+		t.Errorf("unexpected assignments: %+v", got)
+	}
+
+	if _, err := parseFieldFlags([]string{"NoValue"}); err == nil {
+		t.Errorf("expected error for field without '='")
+	}
+}
+
+func TestBuildFieldOperations_Coercion(t *testing.T) {
+	assignments := []fieldAssignment{
+		{Name: "priority", Value: "2"},
+		{Name: "Story Points", Value: "3.5"},
+		{Name: "Custom.Blocked", Value: "true"},
+		{Name: "Due Date", Value: "2025-08-01"},
+		{Name: "Custom.Notes", Value: "<p>hi</p>"},
+	}
+	patchDoc, err := buildFieldOperations(assignments, testFieldDefs, false, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		path  string
+		value interface{}
+	}{
+		{"/fields/Microsoft.VSTS.Common.Priority", 2},
+		{"/fields/Microsoft.VSTS.Scheduling.StoryPoints", 3.5},
+		{"/fields/Custom.Blocked", true},
+		{"/fields/Microsoft.VSTS.Scheduling.DueDate", "2025-08-01T00:00:00Z"},
+		{"/fields/Custom.Notes", "<p>hi</p>"},
+	}
+	if len(patchDoc) != len(want) {
+		t.Fatalf("expected %d operations, got %d", len(want), len(patchDoc))
+	}
+	for i, w := range want {
+		if *patchDoc[i].Path != w.path || patchDoc[i].Value != w.value {
+			t.Errorf("operation %d: got %s=%v (%T), want %s=%v", i, *patchDoc[i].Path, patchDoc[i].Value, patchDoc[i].Value, w.path, w.value)
+		}
+	}
+}
+
+func TestBuildFieldOperations_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		assignment fieldAssignment
+	}{
+		{"unknown field", fieldAssignment{Name: "Nope", Value: "1"}},
+		{"bad integer", fieldAssignment{Name: "Priority", Value: "high"}},
+		{"bad boolean", fieldAssignment{Name: "Blocked", Value: "maybe"}},
+		{"bad date", fieldAssignment{Name: "Due Date", Value: "tomorrow"}},
+		{"empty on create", fieldAssignment{Name: "Priority", Value: ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildFieldOperations([]fieldAssignment{tt.assignment}, testFieldDefs, false, ""); err == nil {
+				t.Errorf("expected error for %+v", tt.assignment)
+			}
+		})
+	}
+}
+
+func TestBuildFieldOperations_OverrideAndRemove(t *testing.T) {
+	assignments := []fieldAssignment{
+		{Name: "Priority", Value: 1},
+		{Name: "Custom.Notes", Value: "old"},
+		{Name: "Microsoft.VSTS.Common.Priority", Value: "3"},
+		{Name: "Notes", Value: ""},
+	}
+	patchDoc, err := buildFieldOperations(assignments, testFieldDefs, true, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patchDoc) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(patchDoc))
+	}
+	if patchDoc[0].Value != 3 {
+		t.Errorf("expected later Priority value to win, got %v", patchDoc[0].Value)
+	}
+	if *patchDoc[1].Op != webapi.OperationValues.Remove {
+		t.Errorf("expected Notes to be removed, got %s", *patchDoc[1].Op)
+	}
+}
+
+func TestReadFieldsFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"fields.yaml": "Priority: 1\nCustom.Notes: some notes\n",
+		"fields.json": `{"Priority": 1, "Custom.Notes": "some notes"}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readFieldsFile(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != 2 || got[0].Name != "Priority" || got[1].Name != "Custom.Notes" {
+				t.Errorf("unexpected assignments: %+v", got)
+			}
+		})
+	}
+}
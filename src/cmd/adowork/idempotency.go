@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/urfave/cli/v3"
+)
+
+// idempotencyTagPrefix prefixes the tag that carries an idempotency key when no field is configured.
+const idempotencyTagPrefix = "adowork-key:"
+
+// idempotencyFlags returns the flags that make creating a work item safe to repeat.
+func idempotencyFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "idempotency-key", Usage: "create the work item only if none carries this key yet", Local: true},
+		&cli.StringFlag{Name: "idempotency-field", Usage: "field that stores the idempotency key (default: a tag " + idempotencyTagPrefix + "<key>)", Local: true},
+		&cli.BoolFlag{Name: "update-existing", Usage: "update the work item found by --idempotency-key with the given values (sent as a single request, never batched)", Local: true},
+	}
+}
+
+// idempotencyKey identifies a work item across repeated runs of the same create command.
+type idempotencyKey struct {
+	Key string
+	// Field is the reference name of the field that stores the key. When empty, the key is stored
+	// as a tag.
+	Field string
+}
+
+// idempotencyKeyFromCommand returns the key given with --idempotency-key, or nil when none was given.
+// A field given by its display name is resolved to its reference name.
+func idempotencyKeyFromCommand(ctx context.Context, cmd *cli.Command, client adoclient.ClientV1) (*idempotencyKey, error) {
+	key := strings.TrimSpace(cmd.String("idempotency-key"))
+	if key == "" {
+		if cmd.Bool("update-existing") {
+			return nil, usageErrorf("--update-existing requires --idempotency-key")
+		}
+		return nil, nil
+	}
+
+	name := cmd.String("idempotency-field")
+	if name == "" {
+		// Tags are separated by ';' and may not contain ','.
+		if strings.ContainsAny(key, ";,") {
+			return nil, usageErrorf("Invalid idempotency key: '%s'. A key stored as a tag cannot contain ';' or ','.", key)
+		}
+		return &idempotencyKey{Key: key}, nil
+	}
+	defs, err := client.GetFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	def, ok := findField(defs, name)
+	if !ok {
+		return nil, usageErrorf("Unknown field: '%s'. Use a field reference name such as 'Custom.IdempotencyKey'.", name)
+	}
+	return &idempotencyKey{Key: key, Field: *def.ReferenceName}, nil
+}
+
+// tag returns the tag that stores the key.
+func (k idempotencyKey) tag() string {
+	return idempotencyTagPrefix + k.Key
+}
+
+// stamp records the key on the work item to create, as a tag or in the configured field.
+func (k idempotencyKey) stamp(spec *workItemSpec, fieldOps []webapi.JsonPatchOperation) []webapi.JsonPatchOperation {
+	if k.Field == "" {
+		spec.Tags = append(spec.Tags, k.tag())
+		return fieldOps
+	}
+	return append(fieldOps, patch.NewOperation(webapi.OperationValues.Add, "/fields/"+k.Field, k.Key))
+}
+
+// wiql returns the query that finds the work items carrying the key, oldest first.
+func (k idempotencyKey) wiql() string {
+	condition := "[System.Tags] CONTAINS " + wiqlQuote(k.tag())
+	if k.Field != "" {
+		condition = fmt.Sprintf("[%s] = %s", k.Field, wiqlQuote(k.Key))
+	}
+	return "SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND " + condition +
+		" ORDER BY [System.Id]"
+}
+
+// findByIdempotencyKey returns the ID of the oldest work item carrying the key, if any.
+func findByIdempotencyKey(ctx context.Context, client adoclient.ClientV1, key idempotencyKey) (int, bool, error) {
+	ids, err := client.QueryByWiql(ctx, key.wiql(), 1)
+	if err != nil || len(ids) == 0 {
+		return 0, false, err
+	}
+	return ids[0], true, nil
+}
+
+// buildExistingUpdatePatchDocument builds the patch document that applies the values of a repeated
+// create to the work item it created before. Empty values are left unchanged rather than removed,
+// and the parent is not linked again, as the work item already has it.
+func buildExistingUpdatePatchDocument(client adoclient.ClientV1, spec workItemSpec, fieldOps []webapi.JsonPatchOperation) ([]webapi.JsonPatchOperation, error) {
+	update := workItemUpdate{Title: &spec.Title}
+	if spec.Description != "" {
+		update.Description = &spec.Description
+	}
+	if spec.AssignedTo != "" {
+		update.AssignedTo = &spec.AssignedTo
+	}
+	fields := patch.New()
+	if spec.Area != "" {
+		fields.Area(spec.Area)
+	}
+	if spec.Iteration != "" {
+		fields.Iteration(spec.Iteration)
+	}
+	var err error
+	if update.Fields, err = fields.Operations(fieldOps...).Build(); err != nil {
+		return nil, err
+	}
+	return buildUpdatePatchDocument(client, update)
+}
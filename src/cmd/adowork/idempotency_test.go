@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/client/fake"
+	"github.com/andreswebs/adowork/config"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+)
+
+// newIdempotentCreateCommand returns a create command running actionWithClient against the client.
+func newIdempotentCreateCommand(client adoclient.ClientV1) *cli.Command {
+	return &cli.Command{
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{Name: "type"},
+			&cli.StringFlag{Name: "title"},
+			&cli.StringFlag{Name: "description"},
+			&cli.StringFlag{Name: "assigned-to"},
+			&cli.IntFlag{Name: "parent"},
+			&cli.BoolFlag{Name: "dry-run"},
+		}, append(fieldFlags(false), idempotencyFlags()...)...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return actionWithClient(ctx, cmd, client, config.ProfileDefaults{})
+		},
+	}
+}
+
+func TestAction_IdempotencyKeyCreatesAndStamps(t *testing.T) {
+	var query string
+	client := newFakeClient()
+	client.Query = func(q string) ([]int, error) {
+		query = q
+		return nil, nil
+	}
+
+	err := newIdempotentCreateCommand(client).Run(context.Background(),
+		[]string{"", "--type", "Bug", "--title", "Build failed", "--idempotency-key", "pipeline-7/run-3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(query, "[System.Tags] CONTAINS 'adowork-key:pipeline-7/run-3'") {
+		t.Errorf("expected a tag lookup, got %q", query)
+	}
+	if got := workItemFields(t, client, 1)[patch.FieldTags]; got != "adowork-key:pipeline-7/run-3" {
+		t.Errorf("expected the key to be stamped as a tag, got %v", got)
+	}
+}
+
+func TestAction_IdempotencyKeyFindsExisting(t *testing.T) {
+	var query string
+	client := newFakeClient()
+	client.Fields = []workitemtracking.WorkItemField{makeFieldDef("Custom.RunKey", "Run Key", workitemtracking.FieldTypeValues.String)}
+	existing := client.AddWorkItem("Bug", map[string]interface{}{patch.FieldTitle: "Build failed", "Custom.RunKey": "it's-7"})
+	client.Query = func(q string) ([]int, error) {
+		query = q
+		return []int{existing}, nil
+	}
+
+	args := []string{"", "--type", "Bug", "--title", "Build failed again", "--idempotency-key", "it's-7", "--idempotency-field", "Run Key"}
+	if err := newIdempotentCreateCommand(client).Run(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(query, "[Custom.RunKey] = 'it''s-7'") {
+		t.Errorf("expected a field lookup with the key quoted, got %q", query)
+	}
+	if _, err := client.GetWorkItem(context.Background(), existing+1); err == nil {
+		t.Error("expected no work item to be created")
+	}
+	if fields := workItemFields(t, client, existing); fields[patch.FieldTitle] != "Build failed" || fields["System.Rev"] != 1 {
+		t.Errorf("expected the existing work item not to be updated without --update-existing, got %v", fields)
+	}
+
+	if err := newIdempotentCreateCommand(client).Run(context.Background(), append(args, "--update-existing")); err != nil {
+		t.Fatal(err)
+	}
+	if fields := workItemFields(t, client, existing); fields[patch.FieldTitle] != "Build failed again" || fields["System.Rev"] != 2 {
+		t.Errorf("expected the title to be updated, got %v", fields)
+	}
+}
+
+func TestIdempotencyKeyFromCommand_Errors(t *testing.T) {
+	client := &fake.Client{}
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"tag separator", []string{"--idempotency-key", "a;b"}, "cannot contain ';' or ','"},
+		{"unknown field", []string{"--idempotency-key", "a", "--idempotency-field", "Nope"}, "Unknown field: 'Nope'"},
+		{"update without key", []string{"--update-existing"}, "--update-existing requires --idempotency-key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			cmd := &cli.Command{
+				Flags: idempotencyFlags(),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					_, err = idempotencyKeyFromCommand(ctx, cmd, client)
+					return nil
+				},
+			}
+			if runErr := cmd.Run(context.Background(), append([]string{""}, tt.args...)); runErr != nil {
+				t.Fatal(runErr)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
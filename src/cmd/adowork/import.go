@@ -0,0 +1,691 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/config"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/andreswebs/adowork/markup"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// importRecord is one work item to create, as read from an import file.
+type importRecord struct {
+	// Line is the line of the record in the import file, used in error messages.
+	Line int
+	// Index is the position of the record in the import file, which orders the summary.
+	Index int
+	// Key identifies the record within the file, so that other records can name it as their parent.
+	Key string
+	// ParentKey is the key of the parent record, whose ID is only known once it is created.
+	ParentKey string
+	Type      string
+	Spec      workItemSpec
+	Fields    []fieldAssignment
+}
+
+// importItem is a validated import record, ready to be created. Spec has the profile defaults applied.
+type importItem struct {
+	Record   importRecord
+	Type     string
+	Spec     workItemSpec
+	FieldOps []webapi.JsonPatchOperation
+	// PatchDoc lacks the parent relation when the parent is another record of the file.
+	PatchDoc []webapi.JsonPatchOperation
+}
+
+// importResult is the outcome of one import item. Skipped items were not attempted.
+type importResult struct {
+	Item    importItem
+	ID      int
+	URL     string
+	Err     error
+	Skipped bool
+}
+
+// importCommand returns the `import` subcommand, which creates work items in bulk from a file.
+func importCommand(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "import",
+		Usage: "Create work items in bulk from a YAML, JSON or CSV file",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "file", Aliases: []string{"F"}, Usage: "file listing the work items (.yaml, .yml, .json or .csv)"},
+			&cli.BoolFlag{Name: "dry-run", Aliases: []string{"n"}, Usage: "validate the file and print the patch documents without creating anything"},
+			descriptionFormatFlag(false),
+			&cli.BoolFlag{Name: "batch", Usage: "create the work items through the $batch endpoint, up to 200 per request (only creates are batched)"},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "number of work items created in parallel (ignored with --batch)",
+				Value: 1,
+				Validator: func(v int) error {
+					if v < 1 {
+						return fmt.Errorf("Invalid concurrency: %d. Use 1 or more.", v)
+					}
+					return nil
+				},
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			client, err := newCLIClient(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			return importActionWithClient(ctx, cmd, client, cfg.Defaults)
+		},
+	}
+}
+
+// importActionWithClient validates every record of the import file, then creates them in order.
+// Nothing is created unless the whole file is valid; after that, a failure only affects its own
+// item and its descendants.
+func importActionWithClient(ctx context.Context, cmd *cli.Command, client adoclient.ClientV1, defaults config.ProfileDefaults) error {
+	if err := checkRequiredFlags(cmd, "file"); err != nil {
+		return err
+	}
+	path := cmd.String("file")
+	defaults.DescriptionFormat = descriptionFormat(cmd, defaults)
+
+	records, err := readImportFile(path)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return usageErrorf("No work items found in '%s'", path)
+	}
+	for i := range records {
+		records[i].Index = i
+	}
+	records, err = orderImportRecords(records)
+	if err != nil {
+		return err
+	}
+
+	items, err := planImport(ctx, client, records, defaults)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Bool("dry-run") {
+		for _, item := range items {
+			fmt.Printf("Line %d: %s %q", item.Record.Line, item.Type, item.Spec.Title)
+			if item.Record.ParentKey != "" {
+				fmt.Printf(" (child of '%s', linked once it is created)", item.Record.ParentKey)
+			}
+			fmt.Println()
+			if err := printDryRun(item.PatchDoc); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var results []importResult
+	if cmd.Bool("batch") {
+		results = executeImportBatch(ctx, client, items)
+	} else {
+		results = executeImport(ctx, client, items, cmd.Int("concurrency"))
+	}
+	printImportSummary(os.Stdout, results)
+
+	if err := importError(results); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readImportFile reads the records of an import file, choosing the format from the file extension.
+func readImportFile(path string) ([]importRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading import file: %w", err)
+	}
+	defer f.Close()
+
+	var records []importRecord
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		records, err = readImportYAML(f)
+	case ".csv":
+		records, err = readImportCSV(f)
+	default:
+		return nil, usageErrorf("Unsupported import file: '%s'. Use a .yaml, .yml, .json or .csv file.", path)
+	}
+	if err != nil {
+		return nil, usageErrorf("Error parsing import file '%s': %w", path, err)
+	}
+	return records, nil
+}
+
+// readImportYAML reads a YAML or JSON list of work items. YAML is a superset of JSON, so a single
+// decoder handles both formats. Standard attributes are top-level keys; other fields go under "fields".
+func readImportYAML(r io.Reader) ([]importRecord, error) {
+	var node yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&node); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	doc := node.Content[0]
+	if doc.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("line %d: expected a list of work items", doc.Line)
+	}
+
+	records := make([]importRecord, 0, len(doc.Content))
+	for _, item := range doc.Content {
+		record, err := readImportRecord(item)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readImportRecord reads a work item object of a YAML or JSON import file.
+func readImportRecord(item *yaml.Node) (importRecord, error) {
+	if item.Kind != yaml.MappingNode {
+		return importRecord{}, fmt.Errorf("line %d: expected a work item object", item.Line)
+	}
+	record := importRecord{Line: item.Line}
+	// Walk the mapping node directly to keep the order of the fields.
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		key, value := item.Content[i], item.Content[i+1]
+		if strings.EqualFold(key.Value, "fields") {
+			fields, err := yamlFieldAssignments(value)
+			if err != nil {
+				return importRecord{}, err
+			}
+			record.Fields = append(record.Fields, fields...)
+			continue
+		}
+		if err := setImportAttribute(&record, key.Value, yamlImportValue(value)); err != nil {
+			return importRecord{}, fmt.Errorf("line %d: %w", key.Line, err)
+		}
+	}
+	return record, nil
+}
+
+// yamlImportValue returns a scalar as is, and a sequence (e.g. of tags) joined with ';'.
+func yamlImportValue(node *yaml.Node) string {
+	if node.Kind != yaml.SequenceNode {
+		return node.Value
+	}
+	values := make([]string, len(node.Content))
+	for i, v := range node.Content {
+		values[i] = v.Value
+	}
+	return strings.Join(values, ";")
+}
+
+// yamlFieldAssignments reads a mapping of field names to values.
+func yamlFieldAssignments(node *yaml.Node) ([]fieldAssignment, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: 'fields' must map field names to values", node.Line)
+	}
+	return mappingFieldAssignments(node, func(key *yaml.Node, err error) error {
+		return fmt.Errorf("line %d: field '%s': %w", key.Line, key.Value, err)
+	})
+}
+
+// readImportCSV reads work items from a CSV file with a header row. Columns named after a standard
+// attribute set it; any other column is a field, named by its reference or display name.
+// Empty cells are ignored.
+func readImportCSV(r io.Reader) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []importRecord
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		record := importRecord{Line: line}
+		for i, column := range header {
+			value := strings.TrimSpace(row[i])
+			if value == "" {
+				continue
+			}
+			if err := setImportAttribute(&record, column, value); errors.Is(err, errUnknownImportAttribute) {
+				record.Fields = append(record.Fields, fieldAssignment{Name: strings.TrimSpace(column), Value: value})
+			} else if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+var errUnknownImportAttribute = errors.New("unknown attribute")
+
+// setImportAttribute sets a standard work item attribute of an import record. Tags are separated by ';'.
+func setImportAttribute(record *importRecord, name, value string) error {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "key":
+		if _, err := strconv.Atoi(value); err == nil {
+			return fmt.Errorf("invalid key: '%s'. Keys must not be numbers, which name existing work items.", value)
+		}
+		record.Key = value
+	case "type":
+		record.Type = value
+	case "title":
+		record.Spec.Title = value
+	case "description":
+		record.Spec.Description = value
+	case "assigned-to", "assignee":
+		record.Spec.AssignedTo = value
+	case "area":
+		record.Spec.Area = value
+	case "iteration":
+		record.Spec.Iteration = value
+	case "parent":
+		if value == "" {
+			return nil
+		}
+		// A number is the ID of an existing work item; anything else is the key of another record.
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			record.ParentKey = value
+			return nil
+		}
+		if id <= 0 {
+			return fmt.Errorf("invalid parent: '%s'. Use a work item ID or the key of another record.", value)
+		}
+		record.Spec.ParentID = &id
+	case "tags":
+		for _, tag := range strings.Split(value, ";") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				record.Spec.Tags = append(record.Spec.Tags, tag)
+			}
+		}
+	default:
+		return fmt.Errorf("%w '%s'. Put other fields under 'fields'.", errUnknownImportAttribute, name)
+	}
+	return nil
+}
+
+// orderImportRecords sorts the records so that every parent comes before its children, keeping the
+// file order otherwise. Duplicate keys, dangling parent keys and cycles are all reported together.
+func orderImportRecords(records []importRecord) ([]importRecord, error) {
+	var problems []string
+	byKey := make(map[string]int, len(records))
+	for i, record := range records {
+		if record.Key == "" {
+			continue
+		}
+		if first, ok := byKey[record.Key]; ok {
+			problems = append(problems, fmt.Sprintf("  - line %d: duplicate key '%s' (first used on line %d)", record.Line, record.Key, records[first].Line))
+			continue
+		}
+		byKey[record.Key] = i
+	}
+	for _, record := range records {
+		if record.ParentKey == "" {
+			continue
+		}
+		if _, ok := byKey[record.ParentKey]; !ok {
+			problems = append(problems, fmt.Sprintf("  - line %d: parent '%s' does not match the key of any record", record.Line, record.ParentKey))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, usageErrorf("Invalid import file (nothing was created):\n%s", strings.Join(problems, "\n"))
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(records))
+	ordered := make([]importRecord, 0, len(records))
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			return usageErrorf("Invalid import file (nothing was created): parent keys form a cycle: %s", strings.Join(append(path, records[i].Key), " -> "))
+		}
+		state[i] = visiting
+		if parent := records[i].ParentKey; parent != "" {
+			if err := visit(byKey[parent], append(path, records[i].Key)); err != nil {
+				return err
+			}
+		}
+		state[i] = done
+		ordered = append(ordered, records[i])
+		return nil
+	}
+	for i := range records {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// planImport validates every record and builds its patch document, applying the profile defaults.
+// All problems are reported together, so a file can be fixed in one pass.
+func planImport(ctx context.Context, client adoclient.ClientV1, records []importRecord, defaults config.ProfileDefaults) ([]importItem, error) {
+	types, err := client.GetWorkItemTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	typeNames := []string{defaults.Type}
+	for _, record := range records {
+		typeNames = append(typeNames, record.Type)
+	}
+	if types, err = freshWorkItemTypes(ctx, client, types, typeNames...); err != nil {
+		return nil, err
+	}
+	var defs []workitemtracking.WorkItemField
+	for _, record := range records {
+		if len(record.Fields) > 0 {
+			if defs, err = client.GetFields(ctx); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	var problems []string
+	items := make([]importItem, 0, len(records))
+	for _, record := range records {
+		item, err := planImportRecord(client, record, types, defs, defaults)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("  - line %d: %v", record.Line, err))
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(problems) > 0 {
+		return nil, usageErrorf("Invalid import file (nothing was created):\n%s", strings.Join(problems, "\n"))
+	}
+	return items, nil
+}
+
+// planImportRecord validates a single record and builds its patch document.
+func planImportRecord(client adoclient.ClientV1, record importRecord, types []workitemtracking.WorkItemType, defs []workitemtracking.WorkItemField, defaults config.ProfileDefaults) (importItem, error) {
+	spec := record.Spec
+	if spec.Title == "" {
+		return importItem{}, fmt.Errorf("missing title")
+	}
+	typeName := record.Type
+	if typeName == "" {
+		typeName = defaults.Type
+	}
+	if typeName == "" {
+		return importItem{}, fmt.Errorf("missing type")
+	}
+	workItemType, err := resolveWorkItemType(types, typeName)
+	if err != nil {
+		return importItem{}, err
+	}
+	if spec.AssignedTo == "" {
+		spec.AssignedTo = defaults.AssignedTo
+	}
+	if spec.Area == "" {
+		spec.Area = defaults.Area
+	}
+	if spec.Iteration == "" {
+		spec.Iteration = defaults.Iteration
+	}
+	if spec.Description, err = markup.ToHTML(spec.Description, defaults.DescriptionFormat); err != nil {
+		return importItem{}, err
+	}
+
+	var fieldOps []webapi.JsonPatchOperation
+	if len(record.Fields) > 0 {
+		if fieldOps, err = buildFieldOperations(record.Fields, defs, false, defaults.DescriptionFormat); err != nil {
+			return importItem{}, err
+		}
+	}
+
+	patchDoc, err := buildCreatePatchDocument(client, spec, fieldOps)
+	if err != nil {
+		return importItem{}, err
+	}
+	return importItem{Record: record, Type: workItemType, Spec: spec, FieldOps: fieldOps, PatchDoc: patchDoc}, nil
+}
+
+// executeImport creates the items with up to concurrency requests in flight, linking children to
+// the IDs of the parents created before them. Items are dispatched in input order, and a child only
+// once its parent is settled; with a concurrency of 1 they are created one by one, in order.
+// Rate-limited requests are retried by the retry layer, which makes every worker back off together.
+// A failure only affects its own item; the descendants of an item that was not created are skipped.
+func executeImport(ctx context.Context, client adoclient.ClientV1, items []importItem, concurrency int) []importResult {
+	results := newImportResults(items)
+	ids := make(map[string]int)
+	var mu sync.Mutex // guards ids
+
+	settled := make([]chan struct{}, len(items))
+	byKey := make(map[string]int)
+	for i, item := range items {
+		settled[i] = make(chan struct{})
+		if item.Record.Key != "" {
+			byKey[item.Record.Key] = i
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				createImportItem(ctx, client, &results[i], ids, &mu)
+				close(settled[i])
+			}
+		}()
+	}
+	for i, item := range items {
+		if parent := item.Record.ParentKey; parent != "" {
+			<-settled[byKey[parent]]
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// createImportItem creates a single import item and records the outcome.
+func createImportItem(ctx context.Context, client adoclient.ClientV1, result *importResult, ids map[string]int, mu *sync.Mutex) {
+	mu.Lock()
+	patchDoc, ok := importPatchDocument(client, result, ids)
+	mu.Unlock()
+	if !ok {
+		return
+	}
+	workItem, err := client.CreateWorkItem(ctx, result.Item.Type, patchDoc)
+	err = explainFieldErrors(ctx, client, result.Item.Type, patchDoc, err)
+	mu.Lock()
+	recordImportResult(client, result, workItem, err, ids)
+	mu.Unlock()
+}
+
+// executeImportBatch creates the items through the $batch endpoint. Items are sent in rounds: a
+// round holds every item whose parent, if any, was settled in an earlier round, so that its real
+// ID can be linked. Within a round, each item succeeds or fails on its own.
+func executeImportBatch(ctx context.Context, client adoclient.ClientV1, items []importItem) []importResult {
+	results := newImportResults(items)
+	ids := make(map[string]int)
+	byKey := make(map[string]int)
+	for i, item := range items {
+		if item.Record.Key != "" {
+			byKey[item.Record.Key] = i
+		}
+	}
+	settled := make([]bool, len(items))
+
+	pending := make([]int, len(items))
+	for i := range pending {
+		pending[i] = i
+	}
+	for len(pending) > 0 {
+		var round, waiting, done []int
+		var ops []adoclient.WorkItemBatchOperation
+		for _, i := range pending {
+			if parent := items[i].Record.ParentKey; parent != "" && !settled[byKey[parent]] {
+				waiting = append(waiting, i)
+				continue
+			}
+			done = append(done, i)
+			if patchDoc, ok := importPatchDocument(client, &results[i], ids); ok {
+				round = append(round, i)
+				ops = append(ops, adoclient.WorkItemBatchOperation{Type: items[i].Type, PatchDoc: patchDoc})
+			}
+		}
+		if len(done) == 0 {
+			// Unreachable for topologically ordered items; guards against looping forever.
+			break
+		}
+
+		if len(ops) > 0 {
+			batch, err := client.BatchWorkItems(ctx, ops)
+			for j, i := range round {
+				if j < len(batch) {
+					err := explainFieldErrors(ctx, client, ops[j].Type, ops[j].PatchDoc, batch[j].Err)
+					recordImportResult(client, &results[i], batch[j].WorkItem, err, ids)
+				} else {
+					recordImportResult(client, &results[i], nil, err, ids)
+				}
+			}
+		}
+		for _, i := range done {
+			settled[i] = true
+		}
+		pending = waiting
+	}
+	return results
+}
+
+// newImportResults returns one pending result per item.
+func newImportResults(items []importItem) []importResult {
+	results := make([]importResult, len(items))
+	for i, item := range items {
+		results[i].Item = item
+	}
+	return results
+}
+
+// importPatchDocument returns the patch document of an item, substituting the ID of its parent
+// record into the parent relation. When the parent was not created, the item is marked as skipped.
+func importPatchDocument(client adoclient.ClientV1, result *importResult, ids map[string]int) ([]webapi.JsonPatchOperation, bool) {
+	item := result.Item
+	if item.Record.ParentKey == "" {
+		return item.PatchDoc, true
+	}
+	parentID, ok := ids[item.Record.ParentKey]
+	if !ok {
+		result.Skipped = true
+		result.Err = fmt.Errorf("parent '%s' was not created", item.Record.ParentKey)
+		return nil, false
+	}
+	spec := item.Spec
+	spec.ParentID = &parentID
+	patchDoc, err := buildCreatePatchDocument(client, spec, item.FieldOps)
+	if err != nil {
+		result.Err = err
+		return nil, false
+	}
+	return patchDoc, true
+}
+
+// recordImportResult stores the outcome of creating an item, and remembers its ID for its children.
+func recordImportResult(client adoclient.ClientV1, result *importResult, workItem *workitemtracking.WorkItem, err error, ids map[string]int) {
+	if err == nil && (workItem == nil || workItem.Id == nil) {
+		err = fmt.Errorf("received no ID from API")
+	}
+	if err != nil {
+		result.Err = adoerrors.FormatADOError(err, "creating work item")
+		return
+	}
+	result.ID = *workItem.Id
+	result.URL = client.GetWorkItemURL(*workItem.Id)
+	if key := result.Item.Record.Key; key != "" {
+		ids[key] = *workItem.Id
+	}
+}
+
+// importError summarizes the items that were not created, or returns nil if all were.
+func importError(results []importResult) error {
+	var problems []string
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			problems = append(problems, fmt.Sprintf("  - line %d: %v", r.Item.Record.Line, r.Err))
+			var fieldErr *adoerrors.FieldValidationError
+			if errors.As(r.Err, &fieldErr) {
+				for _, f := range fieldErr.Fields {
+					problems = append(problems, "      "+f.String())
+				}
+			}
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d work items were not created:\n%s", failed, len(results), strings.Join(problems, "\n"))
+}
+
+// printImportSummary prints the outcome of every import item as an aligned table, in the order of
+// the import file rather than the order the items were created in.
+func printImportSummary(w io.Writer, results []importResult) {
+	results = slices.Clone(results)
+	slices.SortStableFunc(results, func(a, b importResult) int {
+		return cmp.Compare(a.Item.Record.Index, b.Item.Record.Index)
+	})
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tKEY\tSTATUS\tID\tTYPE\tTITLE\tURL")
+	created := 0
+	for _, r := range results {
+		status, id := "created", strconv.Itoa(r.ID)
+		switch {
+		case r.Skipped:
+			status, id = "skipped", "-"
+		case r.Err != nil:
+			status, id = "failed", "-"
+		default:
+			created++
+		}
+		key := r.Item.Record.Key
+		if key == "" {
+			key = "-"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Item.Record.Line, key, status, id, r.Item.Type, r.Item.Spec.Title, valueOrNone(r.URL))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "Created %d of %d work items.\n", created, len(results))
+}
@@ -0,0 +1,407 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/client/fake"
+	"github.com/andreswebs/adowork/config"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+)
+
+func writeImportFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newImportTestCommand(client adoclient.ClientV1, defaults config.ProfileDefaults) *cli.Command {
+	cmd := importCommand(&config.Config{})
+	cmd.Action = func(ctx context.Context, cmd *cli.Command) error {
+		return importActionWithClient(ctx, cmd, client, defaults)
+	}
+	return cmd
+}
+
+// slowClient delays the creates of the fake client by an amount chosen per title, so that
+// concurrent creates overlap, and records how many were in flight at once.
+type slowClient struct {
+	*fake.Client
+	delay func(title string) time.Duration
+
+	mu                    sync.Mutex
+	inFlight, maxInFlight int
+}
+
+func (c *slowClient) CreateWorkItem(ctx context.Context, workItemType string, patchDoc []webapi.JsonPatchOperation) (*workitemtracking.WorkItem, error) {
+	c.mu.Lock()
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	c.mu.Unlock()
+
+	title, _ := patch.FieldValue(patchDoc, patch.FieldTitle).(string)
+	time.Sleep(c.delay(title))
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return c.Client.CreateWorkItem(ctx, workItemType, patchDoc)
+}
+
+// parentURL returns the URL of the parent link of a work item stored by the fake client, if any.
+func parentURL(t *testing.T, client *fake.Client, id int) string {
+	t.Helper()
+	workItem, err := client.GetWorkItem(context.Background(), id)
+	if err != nil {
+		t.Fatalf("expected work item %d to exist: %v", id, err)
+	}
+	for _, rel := range *workItem.Relations {
+		if *rel.Rel == patch.RelParent {
+			return *rel.Url
+		}
+	}
+	return ""
+}
+
+func TestReadImportFile_YAML(t *testing.T) {
+	path := writeImportFile(t, "items.yaml", `
+- type: Bug
+  title: Crash on save
+  assignee: dev@example.com
+  parent: 42
+  tags: [regression, ui]
+  fields:
+    Microsoft.VSTS.Common.Priority: 1
+    Story Points: 3
+- title: Write docs
+`)
+	records, err := readImportFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	r := records[0]
+	if r.Line != 2 || r.Type != "Bug" || r.Spec.Title != "Crash on save" || r.Spec.AssignedTo != "dev@example.com" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	if r.Spec.ParentID == nil || *r.Spec.ParentID != 42 {
+		t.Errorf("expected parent 42, got %v", r.Spec.ParentID)
+	}
+	if strings.Join(r.Spec.Tags, ",") != "regression,ui" {
+		t.Errorf("unexpected tags: %v", r.Spec.Tags)
+	}
+	if len(r.Fields) != 2 || r.Fields[0].Name != "Microsoft.VSTS.Common.Priority" || r.Fields[1].Name != "Story Points" {
+		t.Errorf("expected fields in file order, got %+v", r.Fields)
+	}
+}
+
+func TestReadImportFile_JSON(t *testing.T) {
+	path := writeImportFile(t, "items.json", `[{"type": "Task", "title": "One", "tags": "a; b"}]`)
+	records, err := readImportFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 || records[0].Spec.Title != "One" || len(records[0].Spec.Tags) != 2 {
+		t.Errorf("unexpected records: %+v", records)
+	}
+}
+
+func TestReadImportFile_CSV(t *testing.T) {
+	path := writeImportFile(t, "items.csv", "type,title,tags,Priority\nTask,First,a;b,2\nBug,\"Second, with comma\",,\n")
+	records, err := readImportFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0].Line != 2 || len(records[0].Fields) != 1 || records[0].Fields[0] != (fieldAssignment{Name: "Priority", Value: "2"}) {
+		t.Errorf("unexpected first record: %+v", records[0])
+	}
+	if records[1].Spec.Title != "Second, with comma" || len(records[1].Fields) != 0 {
+		t.Errorf("unexpected second record: %+v", records[1])
+	}
+}
+
+func TestReadImportFile_Errors(t *testing.T) {
+	tests := map[string]string{
+		"items.txt":  "anything",
+		"items.yaml": "- title: x\n  colour: red\n",
+		"list.yaml":  "title: not a list\n",
+		"bad.csv":    "title,parent\nx,-3\n",
+		"key.yaml":   "- key: 12\n  title: numeric key\n",
+	}
+	for name, content := range tests {
+		if _, err := readImportFile(writeImportFile(t, name, content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestImportAction_ValidatesEverythingFirst(t *testing.T) {
+	client := newFakeClient()
+	client.Errors = map[string]error{"CreateWorkItem": errors.New("no work item should be created when the file is invalid")}
+	path := writeImportFile(t, "items.yaml", "- type: Task\n  title: ok\n- type: Nope\n  title: bad type\n- type: Task\n")
+
+	err := newImportTestCommand(client, config.ProfileDefaults{}).Run(context.Background(), []string{"import", "--file", path})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"line 3: Invalid work item type: 'Nope'", "line 5: missing title"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got:\n%s", want, err.Error())
+		}
+	}
+}
+
+func TestImportAction_CreatesInOrderWithDefaults(t *testing.T) {
+	client := newFakeClient()
+	path := writeImportFile(t, "items.csv", "title,type\nFirst,\nSecond,bug\n")
+
+	err := newImportTestCommand(client, config.ProfileDefaults{Type: "Task", Area: "proj\\Team"}).Run(context.Background(), []string{"import", "--file", path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []string{"Task", "Bug"} {
+		id := i + 1
+		fields := workItemFields(t, client, id)
+		if fields["System.WorkItemType"] != want || fields[patch.FieldAreaPath] != "proj\\Team" {
+			t.Errorf("work item %d: expected a %s in the default area, got %v", id, want, fields)
+		}
+	}
+}
+
+func TestExecuteImport_ContinuesAfterFailure(t *testing.T) {
+	client := newFakeClient()
+	items := []importItem{
+		{Record: importRecord{Line: 1, Spec: workItemSpec{Title: "a"}}, Type: "Task"},
+		// Azure DevOps rejects a work item without a title.
+		{Record: importRecord{Line: 2, Key: "b", Spec: workItemSpec{Title: ""}}, Type: "Feature"},
+		{Record: importRecord{Line: 3, ParentKey: "b", Spec: workItemSpec{Title: "child of b"}}, Type: "Task"},
+		{Record: importRecord{Line: 4, Spec: workItemSpec{Title: "d"}}, Type: "Task"},
+	}
+	for i := range items {
+		items[i].Spec = items[i].Record.Spec
+		items[i].PatchDoc = []webapi.JsonPatchOperation{patch.NewOperation(webapi.OperationValues.Add, "/fields/System.Title", items[i].Spec.Title)}
+	}
+
+	results := executeImport(context.Background(), client, items, 1)
+	if results[0].ID != 1 || results[1].Err == nil || !results[2].Skipped || results[3].ID != 2 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	var buf bytes.Buffer
+	printImportSummary(&buf, results)
+	for _, want := range []string{"created", "failed", "skipped", "_workitems/edit/1", "Created 2 of 4 work items."} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected summary to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+
+	err := importError(results)
+	if err == nil || !strings.Contains(err.Error(), "2 of 4 work items were not created") || !strings.Contains(err.Error(), "line 3: parent 'b' was not created") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPrintImportSummary_FileOrder(t *testing.T) {
+	// The parent on line 2 is created before its child on line 1.
+	results := []importResult{
+		{Item: importItem{Record: importRecord{Line: 2, Index: 1, Key: "epic"}, Type: "Epic", Spec: workItemSpec{Title: "parent"}}, ID: 1},
+		{Item: importItem{Record: importRecord{Line: 1, Index: 0, ParentKey: "epic"}, Type: "Feature", Spec: workItemSpec{Title: "child"}}, ID: 2},
+	}
+
+	var buf bytes.Buffer
+	printImportSummary(&buf, results)
+	if child, parent := strings.Index(buf.String(), "child"), strings.Index(buf.String(), "parent"); child < 0 || parent < child {
+		t.Errorf("expected the summary in file order, got:\n%s", buf.String())
+	}
+	if results[0].ID != 1 {
+		t.Errorf("expected the results to be left in creation order")
+	}
+}
+
+func TestExecuteImportBatch_RoundsByHierarchy(t *testing.T) {
+	client := newFakeClient()
+	items := []importItem{
+		{Record: importRecord{Line: 1, Key: "epic", Spec: workItemSpec{Title: "epic"}}, Type: "Epic"},
+		// Azure DevOps rejects a work item without a title.
+		{Record: importRecord{Line: 2, Key: "bad", Spec: workItemSpec{Title: ""}}, Type: "Epic"},
+		{Record: importRecord{Line: 3, Key: "feature", ParentKey: "epic", Spec: workItemSpec{Title: "feature"}}, Type: "Feature"},
+		{Record: importRecord{Line: 4, ParentKey: "bad", Spec: workItemSpec{Title: "orphan"}}, Type: "Feature"},
+		{Record: importRecord{Line: 5, Spec: workItemSpec{Title: "loose"}}, Type: "Task"},
+		{Record: importRecord{Line: 6, ParentKey: "feature", Spec: workItemSpec{Title: "story"}}, Type: "User Story"},
+	}
+	for i := range items {
+		items[i].PatchDoc = []webapi.JsonPatchOperation{patch.NewOperation(webapi.OperationValues.Add, "/fields/System.Title", items[i].Record.Spec.Title)}
+		items[i].Spec = items[i].Record.Spec
+	}
+
+	results := executeImportBatch(context.Background(), client, items)
+
+	// The fake client numbers work items in the order they are created: the top level first,
+	// then each level of children.
+	want := []int{1, 0, 3, 0, 2, 4}
+	for i, w := range want {
+		if results[i].ID != w {
+			t.Errorf("%s: expected ID %d, got %d", items[i].Spec.Title, w, results[i].ID)
+		}
+	}
+	if parent := parentURL(t, client, 4); !strings.HasSuffix(parent, "/3") {
+		t.Errorf("expected the story to be linked to the feature, got %q", parent)
+	}
+	if results[1].Err == nil || !results[3].Skipped {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestOrderImportRecords_ParentsFirst(t *testing.T) {
+	records := []importRecord{
+		{Line: 1, Key: "task", ParentKey: "story", Spec: workItemSpec{Title: "task"}},
+		{Line: 2, Key: "story", ParentKey: "epic", Spec: workItemSpec{Title: "story"}},
+		{Line: 3, Spec: workItemSpec{Title: "standalone"}},
+		{Line: 4, Key: "epic", Spec: workItemSpec{Title: "epic"}},
+	}
+	ordered, err := orderImportRecords(records)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var titles []string
+	for _, r := range ordered {
+		titles = append(titles, r.Spec.Title)
+	}
+	if got := strings.Join(titles, ","); got != "epic,story,task,standalone" {
+		t.Errorf("unexpected order: %s", got)
+	}
+}
+
+func TestOrderImportRecords_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []importRecord
+		want    []string
+	}{
+		{
+			"cycle",
+			[]importRecord{{Line: 1, Key: "a", ParentKey: "b"}, {Line: 2, Key: "b", ParentKey: "a"}},
+			[]string{"cycle: a -> b -> a"},
+		},
+		{
+			"dangling and duplicate",
+			[]importRecord{{Line: 1, Key: "a"}, {Line: 2, Key: "a"}, {Line: 3, ParentKey: "missing"}},
+			[]string{"line 2: duplicate key 'a' (first used on line 1)", "line 3: parent 'missing' does not match"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := orderImportRecords(tt.records)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to contain %q, got:\n%s", want, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestImportAction_Hierarchy(t *testing.T) {
+	client := newFakeClient()
+	path := writeImportFile(t, "plan.yaml", `
+- key: story
+  parent: epic
+  type: User Story
+  title: Story
+- key: epic
+  type: Epic
+  title: Epic
+- type: Task
+  title: Task
+  parent: story
+`)
+
+	if err := newImportTestCommand(client, config.ProfileDefaults{}).Run(context.Background(), []string{"import", "--file", path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Parents are created first: the epic, then the story, then the task.
+	for i, title := range []string{"Epic", "Story", "Task"} {
+		if workItemFields(t, client, i+1)[patch.FieldTitle] != title {
+			t.Errorf("expected work item %d to be the %s", i+1, title)
+		}
+	}
+	if parentURL(t, client, 1) != "" || !strings.HasSuffix(parentURL(t, client, 2), "/1") || !strings.HasSuffix(parentURL(t, client, 3), "/2") {
+		t.Errorf("unexpected parent links: %q, %q, %q", parentURL(t, client, 1), parentURL(t, client, 2), parentURL(t, client, 3))
+	}
+}
+
+func TestExecuteImport_ConcurrentKeepsInputOrder(t *testing.T) {
+	client := &slowClient{Client: newFakeClient(), delay: func(title string) time.Duration {
+		// Later items finish first, so completion order differs from input order.
+		n, _ := strconv.Atoi(strings.TrimPrefix(title, "item "))
+		return time.Duration(20-n) * time.Millisecond
+	}}
+	items := make([]importItem, 20)
+	for i := range items {
+		title := fmt.Sprintf("item %d", i+1)
+		items[i] = importItem{
+			Record:   importRecord{Line: i + 1, Spec: workItemSpec{Title: title}},
+			Type:     "Task",
+			PatchDoc: []webapi.JsonPatchOperation{patch.NewOperation(webapi.OperationValues.Add, "/fields/System.Title", title)},
+		}
+	}
+
+	results := executeImport(context.Background(), client, items, 4)
+	if client.maxInFlight > 4 || client.maxInFlight < 2 {
+		t.Errorf("expected up to 4 concurrent requests, got %d", client.maxInFlight)
+	}
+	for i, r := range results {
+		if r.Err != nil || workItemFields(t, client.Client, r.ID)[patch.FieldTitle] != items[i].Record.Spec.Title {
+			t.Fatalf("result %d: expected the work item created for %q, got ID %d (%v)", i, items[i].Record.Spec.Title, r.ID, r.Err)
+		}
+	}
+}
+
+func TestExecuteImport_ConcurrentWaitsForParent(t *testing.T) {
+	client := &slowClient{Client: newFakeClient(), delay: func(title string) time.Duration {
+		if title == "parent" {
+			return 20 * time.Millisecond
+		}
+		return 0
+	}}
+	items := []importItem{
+		{Record: importRecord{Line: 1, Key: "p", Spec: workItemSpec{Title: "parent"}}, Type: "Feature"},
+		{Record: importRecord{Line: 2, ParentKey: "p", Spec: workItemSpec{Title: "child"}}, Type: "Task"},
+	}
+	for i := range items {
+		items[i].Spec = items[i].Record.Spec
+		items[i].PatchDoc = []webapi.JsonPatchOperation{patch.NewOperation(webapi.OperationValues.Add, "/fields/System.Title", items[i].Spec.Title)}
+	}
+
+	results := executeImport(context.Background(), client, items, 4)
+	if results[0].ID != 1 || results[1].ID != 2 || results[1].Err != nil {
+		t.Fatalf("expected the child to be created after its parent, got %+v", results)
+	}
+	if parent := parentURL(t, client.Client, 2); !strings.HasSuffix(parent, "/1") {
+		t.Errorf("expected the child to be linked to its parent, got %q", parent)
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+// maxIdentityChoices is the number of matching identities above which the search must be refined.
+const maxIdentityChoices = 20
+
+// errPromptAborted is returned when the user declines to create the work item or input ends.
+var errPromptAborted = errors.New("Aborted: no work item was created.")
+
+// prompter asks questions on a terminal. Questions go to out, so that standard output keeps only
+// the result of the command.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// newPrompter returns a prompter reading answers from in and writing questions to out.
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// isTerminal reports whether f is a terminal rather than a file, a pipe or /dev/null.
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// workItemFlagsSet reports whether any flag describing the work item to create was given. These
+// are the local flags of the root command; the others configure every command.
+func workItemFlagsSet(cmd *cli.Command) bool {
+	for _, f := range cmd.Flags {
+		if local, ok := f.(cli.LocalFlag); ok && local.IsLocal() && f.IsSet() {
+			return true
+		}
+	}
+	return false
+}
+
+// readLine reads a line of input. The end of the input aborts.
+func (p *prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line != "" {
+		err = nil
+	}
+	if errors.Is(err, io.EOF) {
+		fmt.Fprintln(p.out)
+		return "", errPromptAborted
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ask asks for a value. An empty answer keeps def, shown in brackets, and "-" gives an empty value.
+func (p *prompter) ask(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", label)
+	}
+	answer, err := p.readLine()
+	if err != nil {
+		return "", err
+	}
+	switch answer = strings.TrimSpace(answer); answer {
+	case "":
+		return def, nil
+	case "-":
+		return "", nil
+	}
+	return answer, nil
+}
+
+// askRequired asks for a value until one is given.
+func (p *prompter) askRequired(label, def string) (string, error) {
+	for {
+		answer, err := p.ask(label, def)
+		if err != nil || answer != "" {
+			return answer, err
+		}
+		fmt.Fprintf(p.out, "%s is required.\n", label)
+	}
+}
+
+// askText reads lines of text up to a line holding only ".". An empty first line gives no text.
+func (p *prompter) askText(label string) (string, error) {
+	fmt.Fprintf(p.out, "%s (end with a line holding only '.', or press Enter for none):\n", label)
+	var lines []string
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if line == "." || (line == "" && len(lines) == 0) {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), " \t\n"), nil
+}
+
+// choose lists the options and asks for one of them, by number or by name. An empty answer keeps
+// def, unless a choice is required and def is empty.
+func (p *prompter) choose(label string, options []string, def string, required bool) (string, error) {
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+	}
+	for {
+		answer, err := p.ask(label, def)
+		if err != nil {
+			return "", err
+		}
+		if answer == "" && !required {
+			return "", nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+		for _, option := range options {
+			if strings.EqualFold(option, answer) {
+				return option, nil
+			}
+		}
+		if answer == def && answer != "" {
+			return def, nil
+		}
+		fmt.Fprintf(p.out, "Enter a number from 1 to %d, or one of the names listed.\n", len(options))
+	}
+}
+
+// confirm asks a yes or no question, answered no by default.
+func (p *prompter) confirm(question string) (bool, error) {
+	for {
+		fmt.Fprintf(p.out, "%s [y/N]: ", question)
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// promptWorkItem asks for the attributes of the work item that were not given with a flag. The
+// values of the record, such as profile defaults, are offered as the default answers.
+func promptWorkItem(ctx context.Context, cmd *cli.Command, client adoclient.ClientV1, types []workitemtracking.WorkItemType, record importRecord, p *prompter) (importRecord, error) {
+	fmt.Fprintln(p.out, "Press Enter to keep the value in brackets, or enter '-' to leave it empty.")
+	var err error
+	if !cmd.IsSet("type") {
+		if record.Type, err = p.choose("Type", workItemTypeNames(types), record.Type, true); err != nil {
+			return importRecord{}, err
+		}
+	}
+	spec := &record.Spec
+	if !cmd.IsSet("title") {
+		if spec.Title, err = p.askRequired("Title", spec.Title); err != nil {
+			return importRecord{}, err
+		}
+	}
+	if !cmd.IsSet("description") && !cmd.IsSet("description-file") {
+		if spec.Description, err = p.askText("Description"); err != nil {
+			return importRecord{}, err
+		}
+	}
+	if !cmd.IsSet("assigned-to") {
+		if spec.AssignedTo, err = promptAssignee(ctx, client, p, spec.AssignedTo); err != nil {
+			return importRecord{}, err
+		}
+	}
+	if !cmd.IsSet("area") {
+		if spec.Area, err = promptClassification(ctx, client, p, "Area", workitemtracking.TreeStructureGroupValues.Areas, spec.Area); err != nil {
+			return importRecord{}, err
+		}
+	}
+	if !cmd.IsSet("iteration") {
+		if spec.Iteration, err = promptClassification(ctx, client, p, "Iteration", workitemtracking.TreeStructureGroupValues.Iterations, spec.Iteration); err != nil {
+			return importRecord{}, err
+		}
+	}
+	if !cmd.IsSet("parent") {
+		if spec.ParentID, err = promptParent(p); err != nil {
+			return importRecord{}, err
+		}
+	}
+	return record, nil
+}
+
+// promptAssignee asks for the assignee and looks the answer up among the identities of the
+// organization, when the client can search them. The default is used as given.
+func promptAssignee(ctx context.Context, client adoclient.ClientV1, p *prompter, def string) (string, error) {
+	searcher, canSearch := client.(adoclient.ClientV2)
+	for {
+		answer, err := p.ask("Assigned to (name or email)", def)
+		if err != nil || answer == "" || answer == def || !canSearch {
+			return answer, err
+		}
+		identities, err := searcher.SearchIdentities(ctx, answer)
+		if err != nil {
+			fmt.Fprintf(p.out, "Could not search users (%v); assigning to '%s' as entered.\n", err, answer)
+			return answer, nil
+		}
+		switch {
+		case len(identities) == 0:
+			fmt.Fprintf(p.out, "No user matches '%s'. Try again, or enter '-' for nobody.\n", answer)
+			continue
+		case len(identities) == 1:
+			fmt.Fprintf(p.out, "Assigning to %s.\n", identities[0])
+			return identities[0].UniqueName, nil
+		case len(identities) > maxIdentityChoices:
+			fmt.Fprintf(p.out, "%d users match '%s'. Enter more of the name or the email.\n", len(identities), answer)
+			continue
+		}
+		names := make([]string, len(identities))
+		for i, identity := range identities {
+			names[i] = identity.String()
+		}
+		choice, err := p.choose("User", names, "", true)
+		if err != nil {
+			return "", err
+		}
+		for _, identity := range identities {
+			if identity.String() == choice {
+				return identity.UniqueName, nil
+			}
+		}
+	}
+}
+
+// promptClassification asks for an area or iteration path among those of the project.
+func promptClassification(ctx context.Context, client adoclient.ClientV1, p *prompter, label string, group workitemtracking.TreeStructureGroup, def string) (string, error) {
+	root, err := client.GetClassificationTree(ctx, group)
+	if err != nil {
+		return "", err
+	}
+	return p.choose(label, classificationPaths(root, ""), def, false)
+}
+
+// classificationPaths lists the paths of a classification node and its descendants, as set in
+// System.AreaPath and System.IterationPath, e.g. `MyProject\Team A`.
+func classificationPaths(node *workitemtracking.WorkItemClassificationNode, parent string) []string {
+	if node == nil || node.Name == nil {
+		return nil
+	}
+	path := *node.Name
+	if parent != "" {
+		path = parent + `\` + path
+	}
+	paths := []string{path}
+	if node.Children != nil {
+		for i := range *node.Children {
+			paths = append(paths, classificationPaths(&(*node.Children)[i], path)...)
+		}
+	}
+	return paths
+}
+
+// promptParent asks for the ID of the parent work item, if any.
+func promptParent(p *prompter) (*int, error) {
+	for {
+		answer, err := p.ask("Parent ID", "")
+		if err != nil || answer == "" {
+			return nil, err
+		}
+		if id, err := strconv.Atoi(answer); err == nil && id > 0 {
+			return &id, nil
+		}
+		fmt.Fprintln(p.out, "Enter a work item ID, or nothing for no parent.")
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/client/fake"
+	"github.com/andreswebs/adowork/config"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+)
+
+func makeClassificationTree(name string, children ...string) *workitemtracking.WorkItemClassificationNode {
+	nodes := make([]workitemtracking.WorkItemClassificationNode, len(children))
+	for i := range children {
+		nodes[i] = workitemtracking.WorkItemClassificationNode{Name: &children[i]}
+	}
+	return &workitemtracking.WorkItemClassificationNode{Name: &name, Children: &nodes}
+}
+
+func newInteractiveTestClient() *fake.Client {
+	return &fake.Client{
+		BaseURL:      "https://dev.azure.com",
+		Organization: "org",
+		Project:      "proj",
+		Types:        makeWorkItemTypes("Bug", "Task"),
+		Areas:        makeClassificationTree("proj", "Team A", "Team B"),
+		Iterations:   makeClassificationTree("proj", "Sprint 1"),
+		Identities: []adoclient.Identity{
+			{DisplayName: "Ana Lima", UniqueName: "ana@example.com"},
+			{DisplayName: "Ana Souza", UniqueName: "souza@example.com"},
+			{DisplayName: "Bo Chen", UniqueName: "bo@example.com"},
+		},
+	}
+}
+
+// runInteractive runs the create command with --interactive, answering the prompts with input.
+func runInteractive(t *testing.T, client adoclient.ClientV1, defaults config.ProfileDefaults, input string, args ...string) (string, error) {
+	t.Helper()
+	var prompts strings.Builder
+	cmd := &cli.Command{
+		Reader:    strings.NewReader(input),
+		ErrWriter: &prompts,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{Name: "type"},
+			&cli.StringFlag{Name: "title"},
+			&cli.StringFlag{Name: "assigned-to"},
+			&cli.StringFlag{Name: "area"},
+			&cli.StringFlag{Name: "iteration"},
+			&cli.IntFlag{Name: "parent"},
+			&cli.BoolFlag{Name: "interactive"},
+			&cli.BoolFlag{Name: "edit"},
+			&cli.BoolFlag{Name: "dry-run"},
+		}, descriptionFlags("description", false)...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return actionWithClient(ctx, cmd, client, defaults)
+		},
+	}
+	err := cmd.Run(context.Background(), append([]string{"adowork", "--interactive"}, args...))
+	return prompts.String(), err
+}
+
+func TestAction_Interactive(t *testing.T) {
+	client := newInteractiveTestClient()
+	parent := client.AddWorkItem("Epic", map[string]interface{}{"System.Title": "Parent"})
+
+	input := strings.Join([]string{
+		"3",           // type: not an option, asked again
+		"task",        // type, by name
+		"",            // title: required, asked again
+		"Write docs",  // title
+		"First line",  // description
+		"",            // a blank line within the description
+		"Second line", //
+		".",           // end of the description
+		"nobody",      // assignee: no match, asked again
+		"ana",         // assignee: two matches
+		"2",           // the second match
+		"",            // area: the profile default
+		"1",           // iteration
+		"x",           // parent: not an ID, asked again
+		"1",           // parent
+		"y",           // confirmation
+	}, "\n") + "\n"
+
+	prompts, err := runInteractive(t, client, config.ProfileDefaults{Area: `proj\Team B`}, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, prompts)
+	}
+	for _, want := range []string{"  2) Task", "Title is required.", "No user matches 'nobody'", "  2) Ana Souza <souza@example.com>", `Area [proj\Team B]: `, "Create this work item? [y/N]: "} {
+		if !strings.Contains(prompts, want) {
+			t.Errorf("expected the prompts to contain %q, got:\n%s", want, prompts)
+		}
+	}
+
+	created, err := client.GetWorkItem(context.Background(), parent+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := *created.Fields
+	want := map[string]interface{}{
+		"System.WorkItemType":    "Task",
+		patch.FieldTitle:         "Write docs",
+		patch.FieldDescription:   "First line\n\nSecond line",
+		patch.FieldAssignedTo:    "souza@example.com",
+		patch.FieldAreaPath:      `proj\Team B`,
+		patch.FieldIterationPath: "proj",
+	}
+	for ref, value := range want {
+		if fields[ref] != value {
+			t.Errorf("expected %s to be %q, got %v", ref, value, fields[ref])
+		}
+	}
+	if created.Relations == nil || len(*created.Relations) != 1 {
+		t.Errorf("expected a parent relation, got %+v", created.Relations)
+	}
+}
+
+func TestAction_InteractiveSkipsFlagsAndAborts(t *testing.T) {
+	client := newInteractiveTestClient()
+	// Only the assignee, area, iteration and parent are asked; the confirmation is declined.
+	prompts, err := runInteractive(t, client, config.ProfileDefaults{}, "bo\n-\n\n\nn\n",
+		"--type", "Bug", "--title", "Crash", "--description", "Steps")
+	if err != errPromptAborted {
+		t.Fatalf("expected the create to be aborted, got %v\n%s", err, prompts)
+	}
+	if strings.Contains(prompts, "Title") || strings.Contains(prompts, "Description") {
+		t.Errorf("expected no prompt for attributes given with flags, got:\n%s", prompts)
+	}
+	if !strings.Contains(prompts, "Assigning to Bo Chen <bo@example.com>.") {
+		t.Errorf("expected the single match to be used, got:\n%s", prompts)
+	}
+	if ids, _ := client.QueryByWiql(context.Background(), "", 0); len(ids) != 0 {
+		t.Errorf("expected nothing to be created, got %v", ids)
+	}
+
+	// Input that ends before the confirmation also aborts.
+	if _, err := runInteractive(t, client, config.ProfileDefaults{}, "bo\n", "--type", "Bug", "--title", "Crash"); err != errPromptAborted {
+		t.Errorf("expected the end of input to abort, got %v", err)
+	}
+}
+
+func TestAction_InteractiveRejectsDescriptionFromStdin(t *testing.T) {
+	_, err := runInteractive(t, newInteractiveTestClient(), config.ProfileDefaults{}, "Steps\n", "--description", "-")
+	if err == nil || !strings.Contains(err.Error(), "cannot both read standard input") {
+		t.Errorf("expected the prompts and the description not to share standard input, got %v", err)
+	}
+}
+
+func TestWorkItemFlagsSet(t *testing.T) {
+	run := func(args ...string) bool {
+		var set bool
+		cmd := &cli.Command{
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "profile"},
+				&cli.StringFlag{Name: "title", Local: true},
+			},
+			Action: func(ctx context.Context, cmd *cli.Command) error {
+				set = workItemFlagsSet(cmd)
+				return nil
+			},
+		}
+		if err := cmd.Run(context.Background(), append([]string{"adowork"}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		return set
+	}
+	if run() || run("--profile", "work") {
+		t.Error("expected configuration flags not to count as work item flags")
+	}
+	if !run("--profile", "work", "--title", "Crash") {
+		t.Error("expected --title to count as a work item flag")
+	}
+}
+
+func TestClassificationPaths(t *testing.T) {
+	root := makeClassificationTree("proj", "Team A")
+	child := "Backend"
+	(*root.Children)[0].Children = &[]workitemtracking.WorkItemClassificationNode{{Name: &child}}
+	got := classificationPaths(root, "")
+	if want := []string{"proj", `proj\Team A`, `proj\Team A\Backend`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,384 @@
+// Command adowork creates and manages Azure DevOps work items from the command line.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/config"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/andreswebs/adowork/markup"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/urfave/cli/v3"
+)
+
+func main() {
+	var cfg config.Config
+	cmd := &cli.Command{
+		Name:    "adowork",
+		Usage:   "A command-line tool for creating Azure DevOps work items",
+		Version: "0.0.1",
+		// Field values such as "Custom.Notes=a, b" may contain commas, so slice flags are never split.
+		DisableSliceFlagSeparator: true,
+		// The configuration flags apply to every command. The remaining root flags create work items;
+		// they are local so they do not leak into subcommands.
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{Name: "profile", Usage: "config file profile to use (or set " + config.EnvADOProfile + ")"},
+			&cli.StringFlag{Name: "org", Usage: "Azure DevOps organization (overrides " + config.EnvADOOrg + ")"},
+			&cli.StringFlag{Name: "project", Usage: "Azure DevOps project (overrides " + config.EnvADOProject + ")"},
+			&cli.StringFlag{Name: "base-url", Usage: "Azure DevOps base URL (overrides " + config.EnvADOBaseURL + ")"},
+			&cli.BoolFlag{Name: "pat-stdin", Usage: "read the PAT from the first line of standard input"},
+			outputFlag(),
+			&cli.IntFlag{Name: "max-retries", Value: adoclient.DefaultMaxRetries, Usage: "retries of a failed request to Azure DevOps (0 disables retries)",
+				Validator: func(v int) error {
+					if v < 0 {
+						return fmt.Errorf("Invalid max retries: %d. Use 0 or more.", v)
+					}
+					return nil
+				}},
+			&cli.DurationFlag{Name: "retry-timeout", Value: adoclient.DefaultRetryTimeout, Usage: "time limit for a request to Azure DevOps including its retries (0 for no limit)"},
+			&cli.StringFlag{Name: "type", Aliases: []string{"t"}, Usage: "work item type (required unless the profile sets a default)", Local: true},
+			&cli.StringFlag{Name: "title", Aliases: []string{"T"}, Usage: "work item title (required)", Local: true},
+			&cli.StringFlag{Name: "assigned-to", Aliases: []string{"a"}, Local: true},
+			&cli.StringFlag{Name: "area", Usage: "area path", Local: true},
+			&cli.StringFlag{Name: "iteration", Aliases: []string{"i"}, Usage: "iteration path", Local: true},
+			&cli.IntFlag{Name: "parent", Aliases: []string{"p"}, Local: true},
+			&cli.BoolFlag{Name: "interactive", Usage: "prompt for the work item attributes not given with a flag, then confirm before creating it (the default on a terminal without flags)", Local: true},
+			&cli.BoolFlag{Name: "edit", Usage: "write the work item in $VISUAL or $EDITOR, starting from the values of the other flags", Local: true},
+			&cli.BoolFlag{Name: "dry-run", Aliases: []string{"n"}, Local: true},
+		}, slices.Concat(descriptionFlags("work item description", true), fieldFlags(true), idempotencyFlags())...),
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			// Missing values are reported by the commands that need a connection.
+			resolved, err := config.Resolve(configFlagsFromCommand(cmd))
+			if err != nil {
+				return ctx, err
+			}
+			cfg = resolved
+			return ctx, nil
+		},
+		Commands: []*cli.Command{
+			updateCommand(&cfg),
+			showCommand(&cfg),
+			queryCommand(&cfg),
+			cacheCommand(&cfg),
+			configCommand(&cfg),
+			importCommand(&cfg),
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			// Without work item flags or arguments, prompt for the work item on a terminal, and display
+			// help text otherwise. Configuration flags such as --profile do not count.
+			if !workItemFlagsSet(cmd) && cmd.Args().Len() == 0 {
+				if !isTerminal(os.Stdin) {
+					return cli.ShowAppHelp(cmd)
+				}
+				if err := cmd.Set("interactive", "true"); err != nil {
+					return err
+				}
+			}
+			if cmd.Bool("interactive") && !isTerminal(os.Stdin) {
+				return usageErrorf("--interactive needs a terminal, but standard input is not one.")
+			}
+			return actionDispatch(ctx, cmd, &cfg)
+		},
+	}
+
+	setOnUsageError(cmd)
+
+	// Every command returns its errors here, to be reported in one place.
+	if err := cmd.Run(context.Background(), os.Args); err != nil {
+		os.Exit(reportError(os.Stderr, err, errorFormatFromCommand(cmd)))
+	}
+}
+
+// errorFormatFromCommand returns the format of error output: --output, or else ADOWORK_ERROR_FORMAT.
+func errorFormatFromCommand(cmd *cli.Command) string {
+	if cmd.IsSet("output") {
+		return cmd.String("output")
+	}
+	return os.Getenv(EnvErrorFormat)
+}
+
+func actionDispatch(ctx context.Context, cmd *cli.Command, cfg *config.Config) error {
+	client, err := newCLIClient(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	return actionWithClient(ctx, cmd, client, cfg.Defaults)
+}
+
+// configFlagsFromCommand returns the configuration overrides given on the command line.
+func configFlagsFromCommand(cmd *cli.Command) config.Flags {
+	return config.Flags{
+		Profile:      cmd.String("profile"),
+		Organization: cmd.String("org"),
+		Project:      cmd.String("project"),
+		BaseURL:      cmd.String("base-url"),
+		PATStdin:     cmd.Bool("pat-stdin"),
+	}
+}
+
+// newCLIClient creates the API client used by commands, serving process metadata from the on-disk cache.
+func newCLIClient(cmd *cli.Command, cfg *config.Config) (adoclient.ClientV1, error) {
+	if _, err := cfg.CheckMissing(); err != nil {
+		return nil, err
+	}
+	client, err := adoclient.New(cfg, retryOption(cmd))
+	if err != nil {
+		return nil, adoerrors.FormatADOError(err, "creating ADO client")
+	}
+	return withMetadataCache(client, cfg), nil
+}
+
+// retryOption returns the retry policy set by --max-retries and --retry-timeout.
+func retryOption(cmd *cli.Command) adoclient.Option {
+	return adoclient.WithRetries(adoclient.RetryPolicy{MaxRetries: cmd.Int("max-retries"), Timeout: cmd.Duration("retry-timeout")})
+}
+
+func actionWithClient(ctx context.Context, cmd *cli.Command, client adoclient.ClientV1, defaults config.ProfileDefaults) error {
+	edit := cmd.Bool("edit")
+	var prompts *prompter
+	if cmd.Bool("interactive") {
+		if edit {
+			return usageErrorf("Use either --interactive or --edit, not both.")
+		}
+		if cmd.String("description") == "-" {
+			return usageErrorf("--description - and --interactive cannot both read standard input.")
+		}
+		prompts = newPrompter(cmd.Root().Reader, cmd.Root().ErrWriter)
+	}
+	if !edit && prompts == nil {
+		required := []string{"title"}
+		if defaults.Type == "" {
+			required = append([]string{"type"}, required...)
+		}
+		if err := checkRequiredFlags(cmd, required...); err != nil {
+			return err
+		}
+	}
+	record, err := createRecordFromCommand(cmd, defaults)
+	if err != nil {
+		return err
+	}
+
+	types, err := client.GetWorkItemTypes(ctx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case edit:
+		record, err = editWorkItem(record)
+	case prompts != nil:
+		record, err = promptWorkItem(ctx, cmd, client, types, record, prompts)
+	}
+	if err != nil {
+		return err
+	}
+	if types, err = freshWorkItemTypes(ctx, client, types, record.Type); err != nil {
+		return err
+	}
+	typeVal, err := resolveWorkItemType(types, record.Type)
+	if err != nil {
+		return err
+	}
+	format := descriptionFormat(cmd, defaults)
+	spec := record.Spec
+	if spec.Description, err = markup.ToHTML(spec.Description, format); err != nil {
+		return err
+	}
+	dryRunVal := cmd.Bool("dry-run")
+
+	fieldOps, err := fieldPatchOperations(ctx, client, record.Fields, false, format)
+	if err != nil {
+		return adoerrors.FormatADOError(err, "building work item patch document")
+	}
+
+	key, err := idempotencyKeyFromCommand(ctx, cmd, client)
+	if err != nil {
+		return adoerrors.FormatADOError(err, "resolving idempotency key")
+	}
+	if key != nil {
+		existingID, found, err := findByIdempotencyKey(ctx, client, *key)
+		if err != nil {
+			return adoerrors.FormatADOError(err, "looking up idempotency key")
+		}
+		if found {
+			return existingWorkItemAction(ctx, cmd, client, existingID, *key, spec, fieldOps)
+		}
+		fieldOps = key.stamp(&spec, fieldOps)
+	}
+
+	patchDoc, err := buildCreatePatchDocument(client, spec, fieldOps)
+	if err != nil {
+		return adoerrors.FormatADOError(err, "building work item patch document")
+	}
+
+	if dryRunVal {
+		return printDryRun(patchDoc)
+	}
+	if prompts != nil {
+		if err := printDryRun(patchDoc); err != nil {
+			return err
+		}
+		confirmed, err := prompts.confirm("Create this work item?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return errPromptAborted
+		}
+	}
+
+	workItem, err := client.CreateWorkItem(ctx, typeVal, patchDoc)
+	if err != nil {
+		return adoerrors.FormatADOError(explainFieldErrors(ctx, client, typeVal, patchDoc, err), "creating work item")
+	}
+
+	if workItem == nil || workItem.Id == nil {
+		return fmt.Errorf("Failed to create work item: received no ID from API")
+	}
+
+	fmt.Print(client.GetWorkItemURL(*workItem.Id))
+
+	return nil
+}
+
+// createRecordFromCommand returns the work item to create as given by the flags, with the profile
+// defaults applied. The description is still in the format given by --description-format.
+func createRecordFromCommand(cmd *cli.Command, defaults config.ProfileDefaults) (importRecord, error) {
+	record := importRecord{
+		Type: stringFlagOrDefault(cmd, "type", defaults.Type),
+		Spec: workItemSpec{
+			Title:      cmd.String("title"),
+			AssignedTo: stringFlagOrDefault(cmd, "assigned-to", defaults.AssignedTo),
+			Area:       stringFlagOrDefault(cmd, "area", defaults.Area),
+			Iteration:  stringFlagOrDefault(cmd, "iteration", defaults.Iteration),
+		},
+	}
+	if parentVal := cmd.Int("parent"); parentVal != 0 {
+		record.Spec.ParentID = &parentVal
+	}
+	description, err := descriptionFromCommand(cmd)
+	if err != nil {
+		return importRecord{}, err
+	}
+	if description != nil {
+		record.Spec.Description = *description
+	}
+	if record.Fields, err = fieldAssignmentsFromCommand(cmd); err != nil {
+		return importRecord{}, adoerrors.FormatADOError(err, "building work item patch document")
+	}
+	return record, nil
+}
+
+// existingWorkItemAction handles a create whose idempotency key is already carried by a work item:
+// it prints the URL of that work item, after updating it when --update-existing is set.
+func existingWorkItemAction(ctx context.Context, cmd *cli.Command, client adoclient.ClientV1, id int, key idempotencyKey, spec workItemSpec, fieldOps []webapi.JsonPatchOperation) error {
+	fmt.Fprintf(os.Stderr, "Work item %d already has idempotency key '%s'; not creating a new one.\n", id, key.Key)
+	if !cmd.Bool("update-existing") {
+		fmt.Print(client.GetWorkItemURL(id))
+		return nil
+	}
+
+	patchDoc, err := buildExistingUpdatePatchDocument(client, spec, fieldOps)
+	if err != nil {
+		return adoerrors.FormatADOError(err, "building work item patch document")
+	}
+	if cmd.Bool("dry-run") {
+		return printDryRun(patchDoc)
+	}
+	if _, err := client.UpdateWorkItem(ctx, id, patchDoc); err != nil {
+		return adoerrors.FormatADOError(explainFieldErrors(ctx, client, workItemTypeOf(ctx, client, id), patchDoc, err), "updating work item")
+	}
+	fmt.Print(client.GetWorkItemURL(id))
+	return nil
+}
+
+// workItemSpec holds the standard attributes of a work item to create.
+type workItemSpec struct {
+	Title       string
+	Description string
+	AssignedTo  string
+	Area        string
+	Iteration   string
+	ParentID    *int
+	Tags        []string
+}
+
+// buildCreatePatchDocument builds the patch document that creates a work item, followed by fieldOps.
+func buildCreatePatchDocument(client adoclient.ClientV1, spec workItemSpec, fieldOps []webapi.JsonPatchOperation) ([]webapi.JsonPatchOperation, error) {
+	b := patch.New().Title(spec.Title)
+	if spec.Description != "" {
+		b.Description(spec.Description)
+	}
+	if spec.AssignedTo != "" {
+		b.AssignedTo(spec.AssignedTo)
+	}
+	if spec.ParentID != nil {
+		b.AddRelation(patch.RelParent, workItemAPIURL(client, *spec.ParentID), nil)
+	}
+	if spec.Area != "" {
+		b.Area(spec.Area)
+	}
+	if spec.Iteration != "" {
+		b.Iteration(spec.Iteration)
+	}
+	if len(spec.Tags) > 0 {
+		b.Tags(spec.Tags...)
+	}
+	return b.Operations(fieldOps...).Build()
+}
+
+// workItemAPIURL returns the URL that relations to the work item point to. It is empty when the
+// client does not implement adoclient.ClientV3, which patch.Builder reports as an invalid relation.
+func workItemAPIURL(client adoclient.ClientV1, workItemID int) string {
+	v3, ok := client.(adoclient.ClientV3)
+	if !ok {
+		return ""
+	}
+	return v3.WorkItemAPIURL(workItemID)
+}
+
+// checkRequiredFlags returns an error listing the named flags that were not set on the command line.
+func checkRequiredFlags(cmd *cli.Command, names ...string) error {
+	var missing []string
+	for _, name := range names {
+		if !cmd.IsSet(name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return usageErrorf("Required flags %q not set", strings.Join(missing, ", "))
+}
+
+// stringFlagOrDefault returns the flag value if it was set, or the given default otherwise.
+func stringFlagOrDefault(cmd *cli.Command, name, def string) string {
+	if cmd.IsSet(name) {
+		return cmd.String(name)
+	}
+	return def
+}
+
+// printDryRun prints the patch document that would be sent to Azure DevOps. The document is
+// checked as it would be before sending, so a dry run fails where the real run would.
+func printDryRun(patchDoc []webapi.JsonPatchOperation) error {
+	patchDoc, err := patch.New().Operations(patchDoc...).Build()
+	if err != nil {
+		return err
+	}
+	jsonBytes, err := json.MarshalIndent(patchDoc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error marshaling dry-run output: %v", err)
+	}
+	fmt.Println("--- Dry Run: Work Item Payload ---")
+	fmt.Println(string(jsonBytes))
+	fmt.Println("------------------------------------")
+	return nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/andreswebs/adowork/client/fake"
+	"github.com/andreswebs/adowork/config"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+)
+
+// newFakeClient returns an in-memory client serving the work item types of the Agile process.
+func newFakeClient() *fake.Client {
+	return &fake.Client{
+		BaseURL:      "https://dev.azure.com",
+		Organization: "mock-org",
+		Project:      "mock-project",
+		Types:        makeWorkItemTypes("Task", "Bug", "User Story", "Feature", "Epic", "Issue"),
+	}
+}
+
+// workItemFields returns the fields of a work item stored by the fake client.
+func workItemFields(t *testing.T, client *fake.Client, id int) map[string]interface{} {
+	t.Helper()
+	workItem, err := client.GetWorkItem(context.Background(), id)
+	if err != nil {
+		t.Fatalf("expected work item %d to exist: %v", id, err)
+	}
+	return *workItem.Fields
+}
+
+// stringPtr returns a pointer to a string.
+func stringPtr(s string) *string {
+	return &s
+}
+
+// makeWorkItemTypes builds work item type definitions with the given names.
+func makeWorkItemTypes(names ...string) []workitemtracking.WorkItemType {
+	types := make([]workitemtracking.WorkItemType, len(names))
+	for i := range names {
+		types[i] = workitemtracking.WorkItemType{Name: &names[i]}
+	}
+	return types
+}
+
+func TestAction_Success(t *testing.T) {
+	client := newFakeClient()
+
+	cmd := &cli.Command{
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "type"},
+			&cli.StringFlag{Name: "title"},
+			&cli.StringFlag{Name: "description"},
+			&cli.StringFlag{Name: "assigned-to"},
+			&cli.IntFlag{Name: "parent"},
+			&cli.BoolFlag{Name: "dry-run"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return actionWithClient(ctx, cmd, client, config.ProfileDefaults{})
+		},
+	}
+
+	err := cmd.Run(context.Background(), []string{"", "--type", "Task", "--title", "Test Task"})
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if fields := workItemFields(t, client, 1); fields[patch.FieldTitle] != "Test Task" || fields["System.WorkItemType"] != "Task" {
+		t.Errorf("Unexpected work item fields: %v", fields)
+	}
+}
+
+func TestAction_CreateWorkItemError(t *testing.T) {
+	client := newFakeClient()
+	client.Errors = map[string]error{"CreateWorkItem": errors.New("API call failed")}
+
+	cmd := &cli.Command{
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "type"},
+			&cli.StringFlag{Name: "title"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return actionWithClient(ctx, cmd, client, config.ProfileDefaults{})
+		},
+	}
+
+	err := cmd.Run(context.Background(), []string{"", "--type", "Bug", "--title", "Test Bug"})
+	if err == nil {
+		t.Fatal("Expected an error, but got none")
+	}
+	if !strings.Contains(err.Error(), "API call failed") {
+		t.Errorf("Expected error message to contain 'API call failed', but got '%s'", err.Error())
+	}
+}
+
+func TestAction_InvalidWorkItemType(t *testing.T) {
+	client := newFakeClient() // Only the type list is needed as it should fail before creating anything.
+
+	cmd := &cli.Command{
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "type"},
+			&cli.StringFlag{Name: "title"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return actionWithClient(ctx, cmd, client, config.ProfileDefaults{})
+		},
+	}
+
+	err := cmd.Run(context.Background(), []string{"", "--type", "InvalidType", "--title", "Test"})
+	if err == nil {
+		t.Fatal("Expected an error for invalid work item type, but got none")
+	}
+	expectedMsg := "Invalid work item type: 'InvalidType'. Available types: Task, Bug, User Story, Feature, Epic, Issue."
+	if err.Error() != expectedMsg {
+		t.Errorf("Expected error message '%s', but got '%s'", expectedMsg, err.Error())
+	}
+	if code := exitCode(err); code != adoerrors.ExitUsage {
+		t.Errorf("Expected exit code %d for an unknown type, got %d", adoerrors.ExitUsage, code)
+	}
+}
+
+// TestCLIErrorWithExec runs the CLI as a subprocess to test error output and exit code.
+func TestCLIErrorWithExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("os/exec CLI test skipped on Windows")
+	}
+	bin := filepath.Join(t.TempDir(), "adowork")
+	build := exec.Command("go", "build", "-o", bin, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build CLI binary: %v\n%s", err, string(out))
+	}
+	cmd := exec.Command(bin, "--type", "Bug", "--title", "Test Bug")
+	cmd.Env = append(os.Environ(),
+		"ADO_ORG=dummy",
+		"ADO_PROJECT=dummy",
+		"ADO_PAT=dummy",
+	)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Errorf("Expected non-zero exit code, got nil error")
+	}
+	// The dummy credentials fail in a way that depends on the environment; the exit code must match
+	// the class of the reported error.
+	outStr := string(output)
+	messages := map[int]string{
+		adoerrors.ExitError:      "An unexpected error occurred",
+		adoerrors.ExitValidation: "Validation error",
+		adoerrors.ExitAuth:       "Authentication failed. Unable to access Azure DevOps with the provided credentials.",
+		adoerrors.ExitNetwork:    "Network error. Unable to connect to Azure DevOps services.",
+	}
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("Expected exec.ExitError, got %T", err)
+	}
+	want, ok := messages[exitErr.ExitCode()]
+	if !ok {
+		t.Errorf("Unexpected exit code %d, output: %s", exitErr.ExitCode(), outStr)
+	} else if !strings.Contains(outStr, want) {
+		t.Errorf("Expected exit code %d to report %q, got: %s", exitErr.ExitCode(), want, outStr)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/config"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+)
+
+// queryFields are the fields fetched for each work item listed by `query`.
+var queryFields = []string{
+	"System.Id",
+	"System.WorkItemType",
+	"System.Title",
+	"System.State",
+	"System.AssignedTo",
+	"System.AreaPath",
+	"System.IterationPath",
+	"System.Tags",
+}
+
+// wiqlFilter holds the convenience filters that are compiled to a WIQL query.
+type wiqlFilter struct {
+	Types      []string
+	States     []string
+	AssignedTo string
+	Area       string
+	Iteration  string
+	Tags       []string
+}
+
+// queryCommand returns the `query` subcommand, which lists work items matching a WIQL query or filters.
+func queryCommand(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:    "query",
+		Aliases: []string{"list"},
+		Usage:   "List work items matching a WIQL query or filters",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "wiql", Usage: "raw WIQL query (overrides the filter flags)"},
+			&cli.StringSliceFlag{Name: "type", Aliases: []string{"t"}, Usage: "work item type (repeatable)"},
+			&cli.StringSliceFlag{Name: "state", Aliases: []string{"s"}, Usage: "work item state (repeatable)"},
+			&cli.StringFlag{Name: "assigned-to", Aliases: []string{"a"}, Usage: "assignee, or @me"},
+			&cli.StringFlag{Name: "area", Usage: "area path (includes child areas)"},
+			&cli.StringFlag{Name: "iteration", Aliases: []string{"i"}, Usage: "iteration path (includes child iterations), or @current"},
+			&cli.StringSliceFlag{Name: "tag", Usage: "tag the work item must carry (repeatable)"},
+			&cli.IntFlag{Name: "top", Usage: "maximum number of work items to return"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			client, err := newCLIClient(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			return queryActionWithClient(ctx, cmd, client)
+		},
+	}
+}
+
+// queryActionWithClient runs the query and prints the matching work items.
+func queryActionWithClient(ctx context.Context, cmd *cli.Command, client adoclient.ClientV1) error {
+	query := cmd.String("wiql")
+	if query == "" {
+		query = buildWiql(wiqlFilter{
+			Types:      cmd.StringSlice("type"),
+			States:     cmd.StringSlice("state"),
+			AssignedTo: cmd.String("assigned-to"),
+			Area:       cmd.String("area"),
+			Iteration:  cmd.String("iteration"),
+			Tags:       cmd.StringSlice("tag"),
+		})
+	}
+
+	ids, err := client.QueryByWiql(ctx, query, cmd.Int("top"))
+	if err != nil {
+		return err
+	}
+
+	workItems, err := fetchWorkItems(ctx, client, ids, queryFields)
+	if err != nil {
+		return err
+	}
+
+	views := make([]workItemView, 0, len(workItems))
+	for i := range workItems {
+		if workItems[i].Id == nil {
+			continue
+		}
+		views = append(views, newWorkItemView(&workItems[i], client.GetWorkItemURL(*workItems[i].Id)))
+	}
+
+	if cmd.String("output") == outputJSON {
+		if err := printJSON(os.Stdout, views); err != nil {
+			return fmt.Errorf("Error marshaling query output: %v", err)
+		}
+		return nil
+	}
+
+	printWorkItemTable(os.Stdout, views)
+	return nil
+}
+
+// buildWiql compiles convenience filters into a WIQL query scoped to the current project.
+func buildWiql(f wiqlFilter) string {
+	conditions := []string{"[System.TeamProject] = @project"}
+
+	if len(f.Types) > 0 {
+		conditions = append(conditions, wiqlIn("System.WorkItemType", f.Types))
+	}
+	if len(f.States) > 0 {
+		conditions = append(conditions, wiqlIn("System.State", f.States))
+	}
+	if f.AssignedTo != "" {
+		conditions = append(conditions, "[System.AssignedTo] = "+wiqlValue(f.AssignedTo))
+	}
+	if f.Area != "" {
+		conditions = append(conditions, "[System.AreaPath] UNDER "+wiqlQuote(f.Area))
+	}
+	if f.Iteration != "" {
+		if strings.EqualFold(f.Iteration, "@current") || strings.EqualFold(f.Iteration, "@currentiteration") {
+			conditions = append(conditions, "[System.IterationPath] = @CurrentIteration")
+		} else {
+			conditions = append(conditions, "[System.IterationPath] UNDER "+wiqlQuote(f.Iteration))
+		}
+	}
+	for _, tag := range f.Tags {
+		conditions = append(conditions, "[System.Tags] CONTAINS "+wiqlQuote(tag))
+	}
+
+	return "SELECT [System.Id] FROM WorkItems WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY [System.ChangedDate] DESC"
+}
+
+// wiqlIn returns an IN condition matching any of the values.
+func wiqlIn(field string, values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = wiqlQuote(v)
+	}
+	return fmt.Sprintf("[%s] IN (%s)", field, strings.Join(quoted, ", "))
+}
+
+// wiqlValue returns a WIQL macro such as @me unchanged, and quotes any other value.
+func wiqlValue(v string) string {
+	if strings.EqualFold(v, "@me") {
+		return "@Me"
+	}
+	return wiqlQuote(v)
+}
+
+// wiqlQuote returns a WIQL string literal, escaping embedded single quotes.
+func wiqlQuote(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
+
+// fetchWorkItems fetches the given work items in batches, preserving the order of ids.
+func fetchWorkItems(ctx context.Context, client adoclient.ClientV1, ids []int, fields []string) ([]workitemtracking.WorkItem, error) {
+	byID := make(map[int]workitemtracking.WorkItem, len(ids))
+	for start := 0; start < len(ids); start += adoclient.MaxBatchSize {
+		end := min(start+adoclient.MaxBatchSize, len(ids))
+		batch, err := client.GetWorkItemsBatch(ctx, ids[start:end], fields)
+		if err != nil {
+			return nil, err
+		}
+		for _, wi := range batch {
+			if wi.Id != nil {
+				byID[*wi.Id] = wi
+			}
+		}
+	}
+
+	workItems := make([]workitemtracking.WorkItem, 0, len(byID))
+	for _, id := range ids {
+		if wi, ok := byID[id]; ok {
+			workItems = append(workItems, wi)
+		}
+	}
+	return workItems, nil
+}
+
+// printWorkItemTable prints work items as an aligned table.
+func printWorkItemTable(w io.Writer, views []workItemView) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tSTATE\tASSIGNED TO\tTITLE")
+	for _, v := range views {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", v.ID, v.Type, v.State, valueOrNone(v.AssignedTo), v.Title)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andreswebs/adowork/client/fake"
+)
+
+func TestBuildWiql(t *testing.T) {
+	got := buildWiql(wiqlFilter{
+		Types:      []string{"Task", "Bug"},
+		States:     []string{"Active"},
+		AssignedTo: "@me",
+		Area:       "proj\\Team's Area",
+		Iteration:  "@current",
+		Tags:       []string{"backend"},
+	})
+	want := "SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project" +
+		" AND [System.WorkItemType] IN ('Task', 'Bug')" +
+		" AND [System.State] IN ('Active')" +
+		" AND [System.AssignedTo] = @Me" +
+		" AND [System.AreaPath] UNDER 'proj\\Team''s Area'" +
+		" AND [System.IterationPath] = @CurrentIteration" +
+		" AND [System.Tags] CONTAINS 'backend'" +
+		" ORDER BY [System.ChangedDate] DESC"
+	if got != want {
+		t.Errorf("buildWiql:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestBuildWiql_NoFilters(t *testing.T) {
+	got := buildWiql(wiqlFilter{})
+	want := "SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project ORDER BY [System.ChangedDate] DESC"
+	if got != want {
+		t.Errorf("buildWiql:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestFetchWorkItems_ChunksAndPreservesOrder(t *testing.T) {
+	ids := make([]int, 450)
+	for i := range ids {
+		ids[i] = 1000 - i
+	}
+
+	// The fake client rejects batches above the API limit, as Azure DevOps does.
+	client := &fake.Client{}
+	for range 1000 {
+		client.AddWorkItem("Task", nil)
+	}
+
+	workItems, err := fetchWorkItems(context.Background(), client, ids, queryFields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(workItems) != len(ids) {
+		t.Fatalf("expected %d work items, got %d", len(ids), len(workItems))
+	}
+	for i, wi := range workItems {
+		if *wi.Id != ids[i] {
+			t.Fatalf("work item %d: got ID %d, want %d", i, *wi.Id, ids[i])
+		}
+	}
+}
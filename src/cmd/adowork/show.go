@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/config"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	outputText string = "text"
+	outputJSON string = "json"
+)
+
+// workItemLink is a non-hierarchical relation of a work item.
+type workItemLink struct {
+	Rel  string `json:"rel"`
+	URL  string `json:"url"`
+	Name string `json:"name,omitempty"`
+}
+
+// workItemView is the rendered representation of a work item used by `show`.
+type workItemView struct {
+	ID            int                    `json:"id"`
+	Rev           int                    `json:"rev"`
+	Type          string                 `json:"type"`
+	Title         string                 `json:"title"`
+	State         string                 `json:"state"`
+	AssignedTo    string                 `json:"assignedTo,omitempty"`
+	AreaPath      string                 `json:"areaPath"`
+	IterationPath string                 `json:"iterationPath"`
+	Parent        *int                   `json:"parent,omitempty"`
+	Children      []int                  `json:"children"`
+	Links         []workItemLink         `json:"links"`
+	URL           string                 `json:"url"`
+	Fields        map[string]interface{} `json:"fields"`
+}
+
+// showCommand returns the `show` subcommand, which fetches and renders a work item.
+func showCommand(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "show",
+		Usage:     "Show a work item",
+		ArgsUsage: "<id>",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			client, err := newCLIClient(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			return showActionWithClient(ctx, cmd, client)
+		},
+	}
+}
+
+// outputFlag returns the --output flag shared by every command. It selects the format of printed
+// work items and of errors.
+func outputFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "output format of work items and errors: text or json",
+		Value:   outputText,
+		Validator: func(v string) error {
+			if v != outputText && v != outputJSON {
+				return fmt.Errorf("Invalid output format: '%s'. Use 'text' or 'json'.", v)
+			}
+			return nil
+		},
+	}
+}
+
+// showActionWithClient fetches the work item given as argument and prints it.
+func showActionWithClient(ctx context.Context, cmd *cli.Command, client adoclient.ClientV1) error {
+	workItemID, err := parseWorkItemID(cmd.Args().First())
+	if err != nil {
+		return err
+	}
+
+	workItem, err := client.GetWorkItem(ctx, workItemID)
+	if err != nil {
+		return err
+	}
+
+	if workItem == nil || workItem.Id == nil {
+		return fmt.Errorf("Failed to get work item: received no ID from API")
+	}
+
+	view := newWorkItemView(workItem, client.GetWorkItemURL(*workItem.Id))
+
+	if cmd.String("output") == outputJSON {
+		if err := printJSON(os.Stdout, view); err != nil {
+			return fmt.Errorf("Error marshaling work item output: %v", err)
+		}
+		return nil
+	}
+
+	printWorkItemText(os.Stdout, view)
+	return nil
+}
+
+// newWorkItemView extracts the displayed attributes from a work item returned by the API.
+func newWorkItemView(workItem *workitemtracking.WorkItem, url string) workItemView {
+	view := workItemView{
+		ID:       *workItem.Id,
+		URL:      url,
+		Children: []int{},
+		Links:    []workItemLink{},
+		Fields:   map[string]interface{}{},
+	}
+	if workItem.Rev != nil {
+		view.Rev = *workItem.Rev
+	}
+	if workItem.Fields != nil {
+		view.Fields = *workItem.Fields
+	}
+
+	view.Type = fieldString(view.Fields, "System.WorkItemType")
+	view.Title = fieldString(view.Fields, "System.Title")
+	view.State = fieldString(view.Fields, "System.State")
+	view.AssignedTo = fieldString(view.Fields, "System.AssignedTo")
+	view.AreaPath = fieldString(view.Fields, "System.AreaPath")
+	view.IterationPath = fieldString(view.Fields, "System.IterationPath")
+
+	if workItem.Relations == nil {
+		return view
+	}
+	for _, rel := range *workItem.Relations {
+		if rel.Rel == nil || rel.Url == nil {
+			continue
+		}
+		switch *rel.Rel {
+		case patch.RelParent:
+			if id, ok := workItemIDFromURL(*rel.Url); ok {
+				view.Parent = &id
+			}
+		case patch.RelChild:
+			if id, ok := workItemIDFromURL(*rel.Url); ok {
+				view.Children = append(view.Children, id)
+			}
+		default:
+			link := workItemLink{Rel: *rel.Rel, URL: *rel.Url}
+			if rel.Attributes != nil {
+				if name, ok := (*rel.Attributes)["name"].(string); ok {
+					link.Name = name
+				}
+			}
+			view.Links = append(view.Links, link)
+		}
+	}
+
+	return view
+}
+
+// printWorkItemText prints a human-readable rendering of a work item.
+func printWorkItemText(w io.Writer, view workItemView) {
+	fmt.Fprintf(w, "%s %d: %s\n", view.Type, view.ID, view.Title)
+	fmt.Fprintf(w, "  State:       %s\n", view.State)
+	fmt.Fprintf(w, "  Assigned To: %s\n", valueOrNone(view.AssignedTo))
+	fmt.Fprintf(w, "  Area:        %s\n", view.AreaPath)
+	fmt.Fprintf(w, "  Iteration:   %s\n", view.IterationPath)
+	fmt.Fprintf(w, "  Revision:    %d\n", view.Rev)
+	if view.Parent != nil {
+		fmt.Fprintf(w, "  Parent:      %d\n", *view.Parent)
+	}
+	if len(view.Children) > 0 {
+		ids := make([]string, len(view.Children))
+		for i, id := range view.Children {
+			ids[i] = strconv.Itoa(id)
+		}
+		fmt.Fprintf(w, "  Children:    %s\n", strings.Join(ids, ", "))
+	}
+	if len(view.Links) > 0 {
+		fmt.Fprintln(w, "  Links:")
+		for _, link := range view.Links {
+			fmt.Fprintf(w, "    - %s: %s\n", link.Rel, link.URL)
+		}
+	}
+	fmt.Fprintf(w, "  URL:         %s\n", view.URL)
+}
+
+// printJSON writes v as indented JSON.
+func printJSON(w io.Writer, v any) error {
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(jsonBytes))
+	return err
+}
+
+// fieldString returns a field value as a display string.
+// Identity fields are rendered as "Display Name <unique name>".
+func fieldString(fields map[string]interface{}, ref string) string {
+	switch v := fields[ref].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}:
+		displayName, _ := v["displayName"].(string)
+		uniqueName, _ := v["uniqueName"].(string)
+		if uniqueName == "" || uniqueName == displayName {
+			return displayName
+		}
+		return fmt.Sprintf("%s <%s>", displayName, uniqueName)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// workItemIDFromURL extracts the work item ID from the last segment of a work item API URL.
+func workItemIDFromURL(url string) (int, bool) {
+	idx := strings.LastIndex(url, "/")
+	id, err := strconv.Atoi(url[idx+1:])
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// valueOrNone returns the value, or a placeholder when it is empty.
+func valueOrNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+func makeTestWorkItem() *workitemtracking.WorkItem {
+	id := 10
+	rev := 4
+	fields := map[string]interface{}{
+		"System.WorkItemType":  "User Story",
+		"System.Title":         "Checkout flow",
+		"System.State":         "Active",
+		"System.AreaPath":      "proj\\Web",
+		"System.IterationPath": "proj\\Sprint 1",
+		"System.AssignedTo": map[string]interface{}{
+			"displayName": "Jane Doe",
+			"uniqueName":  "jane@example.com",
+		},
+	}
+	relations := []workitemtracking.WorkItemRelation{
+		{Rel: stringPtr(patch.RelParent), Url: stringPtr("https://dev.azure.com/org/_apis/wit/workItems/1")},
+		{Rel: stringPtr(patch.RelChild), Url: stringPtr("https://dev.azure.com/org/_apis/wit/workItems/11")},
+		{Rel: stringPtr(patch.RelChild), Url: stringPtr("https://dev.azure.com/org/_apis/wit/workItems/12")},
+		{Rel: stringPtr("Hyperlink"), Url: stringPtr("https://example.com/spec")},
+	}
+	return &workitemtracking.WorkItem{Id: &id, Rev: &rev, Fields: &fields, Relations: &relations}
+}
+
+func TestNewWorkItemView(t *testing.T) {
+	view := newWorkItemView(makeTestWorkItem(), "https://example.com/10")
+
+	if view.Title != "Checkout flow" || view.Type != "User Story" || view.State != "Active" {
+		t.Errorf("unexpected core fields: %+v", view)
+	}
+	if view.AssignedTo != "Jane Doe <jane@example.com>" {
+		t.Errorf("AssignedTo: got %q", view.AssignedTo)
+	}
+	if view.Parent == nil || *view.Parent != 1 {
+		t.Errorf("Parent: got %v, want 1", view.Parent)
+	}
+	if len(view.Children) != 2 || view.Children[0] != 11 || view.Children[1] != 12 {
+		t.Errorf("Children: got %v, want [11 12]", view.Children)
+	}
+	if len(view.Links) != 1 || view.Links[0].Rel != "Hyperlink" {
+		t.Errorf("Links: got %+v", view.Links)
+	}
+}
+
+func TestPrintWorkItemText(t *testing.T) {
+	var buf bytes.Buffer
+	printWorkItemText(&buf, newWorkItemView(makeTestWorkItem(), "https://example.com/10"))
+	out := buf.String()
+	for _, want := range []string{"User Story 10: Checkout flow", "Parent:      1", "Children:    11, 12", "URL:         https://example.com/10"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+// maxSuggestionDistance is the largest edit distance for which a type name is suggested on a typo.
+const maxSuggestionDistance = 3
+
+// workItemTypeNames returns the names of the enabled work item types.
+func workItemTypeNames(types []workitemtracking.WorkItemType) []string {
+	var names []string
+	for _, t := range types {
+		if t.Name == nil || (t.IsDisabled != nil && *t.IsDisabled) {
+			continue
+		}
+		names = append(names, *t.Name)
+	}
+	return names
+}
+
+// freshWorkItemTypes returns types, unless one of names is not among them and the types came from
+// the metadata cache: the cached list may predate a type added since, so it is then fetched once
+// more from the server before the names are resolved.
+func freshWorkItemTypes(ctx context.Context, client adoclient.ClientV1, types []workitemtracking.WorkItemType, names ...string) ([]workitemtracking.WorkItemType, error) {
+	caching, ok := client.(*cachingClient)
+	if !ok {
+		return types, nil
+	}
+	known := workItemTypeNames(types)
+	for _, name := range names {
+		if name != "" && findWorkItemType(known, name) == "" {
+			return caching.refreshWorkItemTypes(ctx)
+		}
+	}
+	return types, nil
+}
+
+// findWorkItemType returns the name among names that matches witType case-insensitively, or an
+// empty string if there is none.
+func findWorkItemType(names []string, witType string) string {
+	normalizedType := strings.TrimSpace(witType)
+	for _, name := range names {
+		if strings.EqualFold(name, normalizedType) {
+			return name
+		}
+	}
+	return ""
+}
+
+// resolveWorkItemType matches the requested type case-insensitively against the project's
+// work item types and returns the server's canonical name.
+func resolveWorkItemType(types []workitemtracking.WorkItemType, witType string) (string, error) {
+	names := workItemTypeNames(types)
+	if name := findWorkItemType(names, witType); name != "" {
+		return name, nil
+	}
+
+	normalizedType := strings.TrimSpace(witType)
+	msg := fmt.Sprintf("Invalid work item type: '%s'.", witType)
+	if suggestion := closestMatch(names, normalizedType); suggestion != "" {
+		msg += fmt.Sprintf(" Did you mean '%s'?", suggestion)
+	}
+	if len(names) > 0 {
+		msg += fmt.Sprintf(" Available types: %s.", strings.Join(names, ", "))
+	}
+	return "", usageErrorf("%s", msg)
+}
+
+// closestMatch returns the candidate closest to s by case-insensitive edit distance,
+// or an empty string if none is close enough.
+func closestMatch(candidates []string, s string) string {
+	best := ""
+	bestDistance := maxSuggestionDistance + 1
+	for _, c := range candidates {
+		d := levenshtein(strings.ToLower(c), strings.ToLower(s))
+		if d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
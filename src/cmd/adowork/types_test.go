@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+func TestResolveWorkItemType(t *testing.T) {
+	disabled := true
+	types := append(makeWorkItemTypes("Product Backlog Item", "Bug", "Impediment"),
+		workitemtracking.WorkItemType{Name: stringPtr("User Story"), IsDisabled: &disabled})
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"product backlog item", "Product Backlog Item", ""},
+		{"IMPEDIMENT", "Impediment", ""},
+		{"Bgu", "", "Did you mean 'Bug'?"},
+		{"User Story", "", "Available types: Product Backlog Item, Bug, Impediment."},
+		{"Something Else", "", "Invalid work item type: 'Something Else'."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := resolveWorkItemType(types, tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveWorkItemType(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"task", "task", 0},
+		{"task", "tsak", 2},
+		{"bug", "bugs", 1},
+		{"epic", "", 4},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
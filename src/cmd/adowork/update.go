@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/config"
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"github.com/andreswebs/adowork/markup"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/urfave/cli/v3"
+)
+
+// updateCommand returns the `update` subcommand, which patches an existing work item.
+func updateCommand(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "update",
+		Usage:     "Update an existing work item",
+		ArgsUsage: "<id>",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{Name: "title", Aliases: []string{"T"}},
+			&cli.StringFlag{Name: "assigned-to", Aliases: []string{"a"}, Usage: "new assignee (empty string unassigns)"},
+			&cli.IntFlag{Name: "parent", Aliases: []string{"p"}, Usage: "ID of the new parent work item, replacing the current parent"},
+			&cli.IntFlag{Name: "expected-rev", Usage: "fail if the work item is no longer at this revision"},
+			&cli.BoolFlag{Name: "dry-run", Aliases: []string{"n"}},
+		}, slices.Concat(descriptionFlags("new description (empty string clears it)", false), fieldFlags(false))...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			client, err := newCLIClient(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			return updateActionWithClient(ctx, cmd, client, cfg.Defaults)
+		},
+	}
+}
+
+// updateActionWithClient builds and applies the update patch document for the work item given as argument.
+func updateActionWithClient(ctx context.Context, cmd *cli.Command, client adoclient.ClientV1, defaults config.ProfileDefaults) error {
+	workItemID, err := parseWorkItemID(cmd.Args().First())
+	if err != nil {
+		return err
+	}
+
+	format := descriptionFormat(cmd, defaults)
+	assignments, err := fieldAssignmentsFromCommand(cmd)
+	if err != nil {
+		return adoerrors.FormatADOError(err, "building work item patch document")
+	}
+	fieldOps, err := fieldPatchOperations(ctx, client, assignments, true, format)
+	if err != nil {
+		return adoerrors.FormatADOError(err, "building work item patch document")
+	}
+
+	description, err := descriptionFromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if description != nil && *description != "" {
+		converted, err := markup.ToHTML(*description, format)
+		if err != nil {
+			return err
+		}
+		description = &converted
+	}
+
+	update := workItemUpdate{
+		Title:       stringFlagPtr(cmd, "title"),
+		Description: description,
+		AssignedTo:  stringFlagPtr(cmd, "assigned-to"),
+		ParentID:    intFlagPtr(cmd, "parent"),
+		ExpectedRev: intFlagPtr(cmd, "expected-rev"),
+		Fields:      fieldOps,
+	}
+	if update.ParentID != nil {
+		if err := replaceParent(ctx, client, workItemID, &update); err != nil {
+			return adoerrors.FormatADOError(err, "reading the current parent")
+		}
+	}
+
+	patchDoc, err := buildUpdatePatchDocument(client, update)
+	if err != nil {
+		return adoerrors.FormatADOError(err, "building work item patch document")
+	}
+
+	if cmd.Bool("dry-run") {
+		return printDryRun(patchDoc)
+	}
+
+	workItem, err := client.UpdateWorkItem(ctx, workItemID, patchDoc)
+	if err != nil {
+		if update.ExpectedRev != nil && adoerrors.IsConflictError(err) {
+			err = fmt.Errorf("work item %d is no longer at revision %d: %w", workItemID, *update.ExpectedRev, err)
+		}
+		if adoerrors.IsValidationError(err) {
+			err = explainFieldErrors(ctx, client, workItemTypeOf(ctx, client, workItemID), patchDoc, err)
+		}
+		return err
+	}
+
+	if workItem == nil || workItem.Id == nil {
+		return fmt.Errorf("Failed to update work item: received no ID from API")
+	}
+
+	fmt.Print(client.GetWorkItemURL(*workItem.Id))
+
+	return nil
+}
+
+// workItemUpdate holds the changes to apply to an existing work item.
+// A nil field is left untouched; a pointer to an empty string clears the field.
+type workItemUpdate struct {
+	Title       *string
+	Description *string
+	AssignedTo  *string
+	ParentID    *int
+	ExpectedRev *int
+	// ParentRelation is the index of the current parent relation, which is removed when ParentID is set.
+	ParentRelation *int
+	// Fields holds additional /fields operations, e.g. from --field.
+	Fields []webapi.JsonPatchOperation
+}
+
+// replaceParent prepares update to move the work item to the parent update.ParentID. A work item
+// has at most one parent, so the current parent relation is removed in the same patch document.
+// Relation indexes change between revisions, so the document then also tests the revision that
+// was read, unless --expected-rev gave one. Nothing changes if the parent is already the same.
+func replaceParent(ctx context.Context, client adoclient.ClientV1, workItemID int, update *workItemUpdate) error {
+	workItem, err := client.GetWorkItem(ctx, workItemID)
+	if err != nil {
+		return err
+	}
+	if workItem.Relations == nil {
+		return nil
+	}
+	for i, relation := range *workItem.Relations {
+		if relation.Rel == nil || *relation.Rel != patch.RelParent {
+			continue
+		}
+		if relation.Url != nil && strings.EqualFold(*relation.Url, workItemAPIURL(client, *update.ParentID)) {
+			update.ParentID = nil
+			return nil
+		}
+		update.ParentRelation = &i
+		if update.ExpectedRev == nil {
+			update.ExpectedRev = workItem.Rev
+		}
+		return nil
+	}
+	return nil
+}
+
+// buildUpdatePatchDocument builds the patch document that updates an existing work item.
+// When ExpectedRev is set, a test operation on /rev comes first so the update fails if the item changed.
+func buildUpdatePatchDocument(client adoclient.ClientV1, update workItemUpdate) ([]webapi.JsonPatchOperation, error) {
+	b := patch.New()
+
+	// Guard against concurrent edits
+	if update.ExpectedRev != nil {
+		b.Test("/rev", *update.ExpectedRev)
+	}
+
+	// Title is mandatory on every work item, so it can be replaced but never removed
+	if update.Title != nil {
+		if *update.Title == "" {
+			return nil, fmt.Errorf("title cannot be empty")
+		}
+		b.ReplaceField(patch.FieldTitle, *update.Title)
+	}
+
+	b.UpdateField(patch.FieldDescription, update.Description).
+		UpdateField(patch.FieldAssignedTo, update.AssignedTo).
+		Operations(update.Fields...)
+
+	if update.ParentID != nil {
+		if update.ParentRelation != nil {
+			b.RemoveRelation(*update.ParentRelation)
+		}
+		b.AddRelation(patch.RelParent, workItemAPIURL(client, *update.ParentID), nil)
+	}
+
+	patchDoc, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	if len(patchDoc) == 0 || (update.ExpectedRev != nil && len(patchDoc) == 1) {
+		return nil, fmt.Errorf("nothing to update: specify at least one field to change")
+	}
+
+	return patchDoc, nil
+}
+
+// parseWorkItemID parses a positional work item ID argument.
+func parseWorkItemID(arg string) (int, error) {
+	if arg == "" {
+		return 0, usageErrorf("Missing work item ID. Usage: adowork <command> <id>")
+	}
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		return 0, usageErrorf("Invalid work item ID: '%s'. It must be a positive integer.", arg)
+	}
+	return id, nil
+}
+
+// stringFlagPtr returns a pointer to the flag value if it was set, or nil otherwise.
+func stringFlagPtr(cmd *cli.Command, name string) *string {
+	if !cmd.IsSet(name) {
+		return nil
+	}
+	v := cmd.String(name)
+	return &v
+}
+
+// intFlagPtr returns a pointer to the flag value if it was set, or nil otherwise.
+func intFlagPtr(cmd *cli.Command, name string) *int {
+	if !cmd.IsSet(name) {
+		return nil
+	}
+	v := cmd.Int(name)
+	return &v
+}
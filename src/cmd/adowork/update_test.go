@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	adoclient "github.com/andreswebs/adowork/client"
+	"github.com/andreswebs/adowork/client/fake"
+	"github.com/andreswebs/adowork/config"
+	"github.com/andreswebs/adowork/markup"
+	"github.com/andreswebs/adowork/patch"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/urfave/cli/v3"
+)
+
+func newUpdateTestCommand(client adoclient.ClientV1) *cli.Command {
+	cmd := updateCommand(&config.Config{})
+	cmd.Action = func(ctx context.Context, cmd *cli.Command) error {
+		return updateActionWithClient(ctx, cmd, client, config.ProfileDefaults{})
+	}
+	return cmd
+}
+
+func TestUpdateAction_Success(t *testing.T) {
+	client := newFakeClient()
+	id := client.AddWorkItem("Task", map[string]interface{}{patch.FieldTitle: "Original"})
+
+	err := newUpdateTestCommand(client).Run(context.Background(), []string{"update", "--title", "Renamed", strconv.Itoa(id)})
+	if err != nil {
+		t.Errorf("Expected no error, but got: %v", err)
+	}
+	if got := workItemFields(t, client, id)[patch.FieldTitle]; got != "Renamed" {
+		t.Errorf("Expected work item %d to be renamed, got %v", id, got)
+	}
+}
+
+// addChild creates a task under parent and returns its ID.
+func addChild(t *testing.T, client *fake.Client, parent int) int {
+	t.Helper()
+	doc, err := patch.New().Title("Child").AddRelation(patch.RelParent, client.WorkItemAPIURL(parent), nil).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	workItem, err := client.CreateWorkItem(context.Background(), "Task", doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *workItem.Id
+}
+
+func TestUpdateAction_ReplacesParent(t *testing.T) {
+	client := newFakeClient()
+	oldParent := client.AddWorkItem("Epic", map[string]interface{}{patch.FieldTitle: "Old"})
+	newParent := client.AddWorkItem("Epic", map[string]interface{}{patch.FieldTitle: "New"})
+	child := addChild(t, client, oldParent)
+	id := strconv.Itoa(child)
+
+	for _, parent := range []int{newParent, newParent} {
+		err := newUpdateTestCommand(client).Run(context.Background(), []string{"update", "--title", "Child", "--parent", strconv.Itoa(parent), id})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	}
+
+	workItem, err := client.GetWorkItem(context.Background(), child)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if workItem.Relations == nil || len(*workItem.Relations) != 1 {
+		t.Fatalf("expected exactly one relation, got %v", workItem.Relations)
+	}
+	if relation := (*workItem.Relations)[0]; *relation.Rel != patch.RelParent || *relation.Url != client.WorkItemAPIURL(newParent) {
+		t.Errorf("expected the parent to be work item %d, got %s %s", newParent, *relation.Rel, *relation.Url)
+	}
+}
+
+func TestReplaceParent_TestsRevision(t *testing.T) {
+	client := newFakeClient()
+	parent := client.AddWorkItem("Epic", map[string]interface{}{patch.FieldTitle: "Old"})
+	child := addChild(t, client, parent)
+
+	newParent := parent + 10
+	update := workItemUpdate{ParentID: &newParent}
+	if err := replaceParent(context.Background(), client, child, &update); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := buildUpdatePatchDocument(client, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc) != 3 || *doc[0].Path != "/rev" || *doc[1].Op != webapi.OperationValues.Remove || *doc[1].Path != "/relations/0" {
+		t.Errorf("expected a /rev test and the removal of the current parent, got %v", doc)
+	}
+}
+
+func TestUpdateAction_InvalidID(t *testing.T) {
+	err := newUpdateTestCommand(newFakeClient()).Run(context.Background(), []string{"update", "--title", "Renamed", "abc"})
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if !strings.Contains(err.Error(), "Invalid work item ID") {
+		t.Errorf("Expected invalid ID error, got '%s'", err.Error())
+	}
+}
+
+func TestUpdateAction_DescriptionFormat(t *testing.T) {
+	client := newFakeClient()
+	client.Fields = testFieldDefs
+	workItem := client.AddWorkItem("Task", map[string]interface{}{patch.FieldTitle: "Notes"})
+	id := strconv.Itoa(workItem)
+	fields := func() map[string]interface{} { return workItemFields(t, client, workItem) }
+
+	cmd := updateCommand(&config.Config{})
+	cmd.Action = func(ctx context.Context, cmd *cli.Command) error {
+		return updateActionWithClient(ctx, cmd, client, config.ProfileDefaults{DescriptionFormat: markup.FormatMarkdown})
+	}
+	err := cmd.Run(context.Background(), []string{"update", "--description", "- one\n- two", "--field", "Notes=**done**", "--field", "Priority=1", id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fields()[patch.FieldDescription]; got != "<ul>\n<li>one</li>\n<li>two</li>\n</ul>" {
+		t.Errorf("expected the description as an HTML list, got %q", got)
+	}
+	if got := fields()["Custom.Notes"]; got != "<p><strong>done</strong></p>" {
+		t.Errorf("expected the HTML field to be converted, got %q", got)
+	}
+	if got := fields()["Microsoft.VSTS.Common.Priority"]; got != 1 {
+		t.Errorf("expected other fields to be left alone, got %v", got)
+	}
+
+	err = newUpdateTestCommand(client).Run(context.Background(), []string{"update", "--description", "a < b", "--description-format", "text", id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fields()[patch.FieldDescription]; got != "a &lt; b" {
+		t.Errorf("expected --description-format to override the profile, got %q", got)
+	}
+
+	err = newUpdateTestCommand(client).Run(context.Background(), []string{"update", "--description", "x", "--description-format", "rtf", id})
+	if err == nil || !strings.Contains(err.Error(), "Invalid description format: 'rtf'") {
+		t.Errorf("expected an invalid format error, got %v", err)
+	}
+}
+
+func TestBuildUpdatePatchDocument(t *testing.T) {
+	client := newFakeClient()
+	title := "New title"
+	empty := ""
+	assignee := "someone@example.com"
+	parent := 42
+	rev := 7
+
+	patchDoc, err := buildUpdatePatchDocument(client, workItemUpdate{
+		Title:       &title,
+		Description: &empty,
+		AssignedTo:  &assignee,
+		ParentID:    &parent,
+		ExpectedRev: &rev,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		op   webapi.Operation
+		path string
+	}{
+		{webapi.OperationValues.Test, "/rev"},
+		{webapi.OperationValues.Replace, "/fields/System.Title"},
+		{webapi.OperationValues.Remove, "/fields/System.Description"},
+		{webapi.OperationValues.Add, "/fields/System.AssignedTo"},
+		{webapi.OperationValues.Add, "/relations/-"},
+	}
+	if len(patchDoc) != len(want) {
+		t.Fatalf("expected %d operations, got %d", len(want), len(patchDoc))
+	}
+	for i, w := range want {
+		if *patchDoc[i].Op != w.op || *patchDoc[i].Path != w.path {
+			t.Errorf("operation %d: got %s %s, want %s %s", i, *patchDoc[i].Op, *patchDoc[i].Path, w.op, w.path)
+		}
+	}
+	if patchDoc[0].Value != rev {
+		t.Errorf("expected /rev test value %d, got %v", rev, patchDoc[0].Value)
+	}
+}
+
+func TestBuildUpdatePatchDocument_Errors(t *testing.T) {
+	client := newFakeClient()
+	empty := ""
+	rev := 3
+	tests := []struct {
+		name    string
+		update  workItemUpdate
+		wantErr string
+	}{
+		{"no changes", workItemUpdate{}, "nothing to update"},
+		{"only expected rev", workItemUpdate{ExpectedRev: &rev}, "nothing to update"},
+		{"empty title", workItemUpdate{Title: &empty}, "title cannot be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildUpdatePatchDocument(client, tt.update)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
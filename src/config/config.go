@@ -0,0 +1,523 @@
+// Package config resolves the settings adowork needs to connect to Azure DevOps from the config
+// file, ADO_* environment variables and command-line overrides.
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	adoerrors "github.com/andreswebs/adowork/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvADOOrg      string = "ADO_ORG"
+	EnvADOProject  string = "ADO_PROJECT"
+	EnvADOPAT      string = "ADO_PAT"
+	EnvADOBaseURL  string = "ADO_BASE_URL"
+	EnvADOProfile  string = "ADO_PROFILE"
+	EnvConfigFile  string = "ADOWORK_CONFIG"
+	DefaultBaseURL string = "https://dev.azure.com"
+	DefaultProfile string = "default"
+)
+
+// Configuration sources, from lowest to highest precedence: default < file < env < flag.
+const (
+	SourceDefault string = "default"
+	SourceFile    string = "file"
+	SourceEnv     string = "env"
+	SourceFlag    string = "flag"
+)
+
+// Configuration setting keys, as used in the config file and reported by `config view`.
+const (
+	KeyOrganization string = "organization"
+	KeyProject      string = "project"
+	KeyBaseURL      string = "base-url"
+	KeyPAT          string = "pat"
+	KeyToken        string = "token"
+)
+
+type Config struct {
+	Organization string `validate:"required" env:"ADO_ORG"`
+	Project      string `validate:"required" env:"ADO_PROJECT"`
+	PAT          string `validate:"required" env:"ADO_PAT"`
+	BaseURL      string `env:"ADO_BASE_URL"`
+	// Token is an Entra ID (OAuth) access token, sent as a bearer token instead of a PAT.
+	Token string `env:"ADO_TOKEN"`
+
+	// Profile is the name of the config file profile in use, if any.
+	Profile string
+	// ConfigPath is the path of the config file, whether or not it exists.
+	ConfigPath string
+	// Defaults are the profile's default values for new work items.
+	Defaults ProfileDefaults
+	// Credential describes where the profile reads its PAT from.
+	Credential CredentialSource
+	// TokenSource describes where the profile reads its access token from.
+	TokenSource TokenSource
+	// Auth is the setting used to authenticate: KeyPAT, or KeyToken for a bearer token.
+	Auth string
+	// Credentials supplies the PAT or token when it is not given directly; it is only asked when
+	// a client is created.
+	Credentials CredentialProvider
+	// Sources records where each setting was resolved from, keyed by setting key.
+	Sources map[string]string
+
+	// profileMissing is set when the selected profile is not defined in the config file.
+	profileMissing bool
+}
+
+// File is the on-disk configuration, holding named profiles.
+type File struct {
+	CurrentProfile string             `yaml:"current-profile,omitempty"`
+	Profiles       map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+// Profile holds the connection settings and defaults for one organization/project.
+type Profile struct {
+	Organization string           `yaml:"organization,omitempty"`
+	Project      string           `yaml:"project,omitempty"`
+	BaseURL      string           `yaml:"base-url,omitempty"`
+	Defaults     ProfileDefaults  `yaml:"defaults,omitempty"`
+	Credential   CredentialSource `yaml:"credential,omitempty"`
+	Token        TokenSource      `yaml:"token,omitempty"`
+}
+
+// ProfileDefaults are applied to new work items when the matching flag is not given.
+type ProfileDefaults struct {
+	Type       string `yaml:"type,omitempty"`
+	Area       string `yaml:"area,omitempty"`
+	Iteration  string `yaml:"iteration,omitempty"`
+	AssignedTo string `yaml:"assigned-to,omitempty"`
+	// DescriptionFormat is the format of descriptions and other HTML field values: markdown, html or text.
+	DescriptionFormat string `yaml:"description-format,omitempty"`
+}
+
+// CredentialSource names where the PAT is read from when ADO_PAT is not set.
+// When several are given, Env is preferred over File, and File over Command.
+type CredentialSource struct {
+	// Env is the name of an environment variable holding the PAT.
+	Env string `yaml:"env,omitempty"`
+	// File is the path of a file holding the PAT.
+	File string `yaml:"file,omitempty"`
+	// Command is a git-credential-style helper that prints the PAT.
+	Command string `yaml:"command,omitempty"`
+}
+
+// provider returns the credential provider described by the source, or nil if none is set.
+func (s CredentialSource) provider() CredentialProvider {
+	switch {
+	case s.Env != "":
+		return EnvCredential{Name: s.Env}
+	case s.File != "":
+		return FileCredential{Path: s.File}
+	case s.Command != "":
+		return CommandCredential{Command: s.Command}
+	}
+	return nil
+}
+
+// TokenSource names where an Entra ID access token is read from. A token takes precedence over a
+// PAT configured at the same level. When several are given, Env is preferred over File, and File
+// over Command.
+type TokenSource struct {
+	// Env is the name of an environment variable holding the token.
+	Env string `yaml:"env,omitempty"`
+	// File is the path of a file holding the token.
+	File string `yaml:"file,omitempty"`
+	// Command prints the token, e.g. `az account get-access-token`.
+	Command string `yaml:"command,omitempty"`
+}
+
+// provider returns the credential provider described by the source, or nil if none is set.
+func (s TokenSource) provider() CredentialProvider {
+	switch {
+	case s.Env != "":
+		return EnvCredential{Name: s.Env}
+	case s.File != "":
+		return FileCredential{Path: s.File}
+	case s.Command != "":
+		return TokenCommandCredential{Command: s.Command}
+	}
+	return nil
+}
+
+// Flags are the command-line overrides for configuration settings.
+type Flags struct {
+	Profile      string
+	Organization string
+	Project      string
+	BaseURL      string
+	// PATStdin reads the PAT from standard input.
+	PATStdin bool
+}
+
+// ProfileKeys lists the settings that can be stored in a profile, in display order.
+var ProfileKeys = []string{
+	KeyOrganization,
+	KeyProject,
+	KeyBaseURL,
+	"credential.env",
+	"credential.file",
+	"credential.command",
+	"token.env",
+	"token.file",
+	"token.command",
+	"defaults.type",
+	"defaults.area",
+	"defaults.iteration",
+	"defaults.assigned-to",
+	"defaults.description-format",
+}
+
+// Setting returns a pointer to the profile value stored under key, and false for an unknown key.
+func (p *Profile) Setting(key string) (*string, bool) {
+	switch key {
+	case KeyOrganization:
+		return &p.Organization, true
+	case KeyProject:
+		return &p.Project, true
+	case KeyBaseURL:
+		return &p.BaseURL, true
+	case "credential.env":
+		return &p.Credential.Env, true
+	case "credential.file":
+		return &p.Credential.File, true
+	case "credential.command":
+		return &p.Credential.Command, true
+	case "token.env":
+		return &p.Token.Env, true
+	case "token.file":
+		return &p.Token.File, true
+	case "token.command":
+		return &p.Token.Command, true
+	case "defaults.type":
+		return &p.Defaults.Type, true
+	case "defaults.area":
+		return &p.Defaults.Area, true
+	case "defaults.iteration":
+		return &p.Defaults.Iteration, true
+	case "defaults.assigned-to":
+		return &p.Defaults.AssignedTo, true
+	case "defaults.description-format":
+		return &p.Defaults.DescriptionFormat, true
+	}
+	return nil, false
+}
+
+// normalizeBaseURL returns the base URL for Azure DevOps, ensuring it does not end with a slash.
+func (c *Config) normalizeBaseURL() string {
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	} else {
+		re := regexp.MustCompile(`/+$`)
+		baseURL = re.ReplaceAllString(baseURL, "")
+	}
+	return baseURL
+}
+
+// set assigns a non-empty value to a setting and records its source.
+func (c *Config) set(key string, dst *string, value, source string) {
+	if value == "" {
+		return
+	}
+	*dst = value
+	if c.Sources == nil {
+		c.Sources = make(map[string]string)
+	}
+	c.Sources[key] = source
+}
+
+// setCredential selects the provider of the PAT (key KeyPAT) or bearer token (key KeyToken) and
+// records its source, replacing any credential set before. An environment variable is read right
+// away, and ignored when it is not set; other providers are only asked when a client is created.
+func (c *Config) setCredential(key string, p CredentialProvider, source string) {
+	if p == nil {
+		return
+	}
+	secret := ""
+	if env, ok := p.(EnvCredential); ok {
+		if secret = os.Getenv(env.Name); secret == "" {
+			return
+		}
+	}
+	c.PAT, c.Token = "", ""
+	*c.secret(key) = secret
+	c.Auth = key
+	c.Credentials = p
+	if c.Sources == nil {
+		c.Sources = make(map[string]string)
+	}
+	delete(c.Sources, KeyPAT)
+	delete(c.Sources, KeyToken)
+	c.Sources[key] = source
+}
+
+// secret returns a pointer to the PAT or the token, depending on key.
+func (c *Config) secret(key string) *string {
+	if key == KeyToken {
+		return &c.Token
+	}
+	return &c.PAT
+}
+
+// UsesToken reports whether the client authenticates with a bearer token rather than a PAT.
+func (c *Config) UsesToken() bool {
+	return c.Auth == KeyToken || (c.Auth == "" && c.PAT == "" && c.Token != "")
+}
+
+// ResolveCredential returns the PAT or token, asking the credential provider for it if it was not given directly.
+func (c *Config) ResolveCredential(ctx context.Context) (string, error) {
+	key := KeyPAT
+	if c.UsesToken() {
+		key = KeyToken
+	}
+	secret := c.secret(key)
+	if *secret != "" {
+		return *secret, nil
+	}
+	if c.Credentials == nil {
+		return "", fmt.Errorf("No credential configured")
+	}
+	value, err := c.Credentials.Credential(ctx, c.OrganizationURL())
+	if err != nil {
+		return "", err
+	}
+	*secret = value
+	return value, nil
+}
+
+// DescribeCredential returns the redacted PAT or token (key KeyPAT or KeyToken), or where it will
+// be read from when it is only read when a client is created.
+func (c *Config) DescribeCredential(key string) string {
+	secret := *c.secret(key)
+	if secret == "" && c.Auth == key && c.Credentials != nil {
+		return "(read from " + c.Credentials.String() + ")"
+	}
+	return redactSecret(secret)
+}
+
+// redactSecret hides a secret, keeping its last four characters when it is long enough to stay unguessable.
+func redactSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) <= 8 {
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
+
+// ProfileMissing reports whether the selected profile is not defined in the config file.
+func (c *Config) ProfileMissing() bool {
+	return c.profileMissing
+}
+
+// OrganizationURL returns the URL of the Azure DevOps organization.
+func (c *Config) OrganizationURL() string {
+	return fmt.Sprintf("%s/%s", c.BaseURL, c.Organization)
+}
+
+// CheckMissing checks that all required fields in Config are non-empty.
+// Returns an error if any are missing.
+func (c *Config) CheckMissing() (missing []string, err error) {
+	if c.profileMissing {
+		return nil, &adoerrors.ConfigError{Err: fmt.Errorf("Profile %q not found in %s", c.Profile, c.ConfigPath)}
+	}
+	if c.Organization == "" {
+		missing = append(missing, EnvADOOrg)
+	}
+	if c.Project == "" {
+		missing = append(missing, EnvADOProject)
+	}
+	// Either a PAT or a bearer token authenticates the client.
+	if c.PAT == "" && c.Token == "" && c.Credentials == nil {
+		missing = append(missing, EnvADOPAT)
+	}
+	if c.BaseURL == "" {
+		missing = append(missing, EnvADOBaseURL)
+	}
+	err = formatMissingEnvError(missing, c.Profile, c.ConfigPath)
+	return
+}
+
+// settingSources describes, for each required env var, the other env vars, flag and config file keys
+// that can also provide it.
+var settingSources = map[string]struct {
+	envs []string
+	flag string
+	keys []string
+}{
+	EnvADOOrg:     {nil, "--org", []string{KeyOrganization}},
+	EnvADOProject: {nil, "--project", []string{KeyProject}},
+	EnvADOPAT:     {[]string{EnvADOPATFile, EnvADOToken, EnvADOTokenFile, EnvADOTokenCommand}, "--pat-stdin", []string{"credential", KeyToken}},
+	EnvADOBaseURL: {nil, "--base-url", []string{KeyBaseURL}},
+}
+
+// formatMissingEnvError returns a grouped, user-friendly error message for missing settings,
+// naming every source each value was expected from.
+func formatMissingEnvError(missing []string, profile, configFile string) error {
+	if len(missing) == 0 {
+		return nil
+	}
+	msg := "Missing required configuration:\n"
+	for _, env := range missing {
+		sources := []string{"env " + env}
+		if s, ok := settingSources[env]; ok {
+			for _, alt := range s.envs {
+				sources = append(sources, "env "+alt)
+			}
+			if s.flag != "" {
+				sources = append(sources, "flag "+s.flag)
+			}
+			if profile != "" {
+				sources = append(sources, fmt.Sprintf("'%s' in profile %q of %s", strings.Join(s.keys, "' or '"), profile, configFile))
+			}
+		}
+		msg += "  - " + env + " (expected from " + strings.Join(sources, ", ") + ")\n"
+	}
+	msg += "\nPlease set the above variables in your environment. Example (bash/zsh):\n"
+	for _, env := range missing {
+		msg += "  export " + env + "=value\n"
+	}
+	if profile == "" && configFile != "" {
+		msg += "\nAlternatively, define a profile in " + configFile + " and select it with --profile or " + EnvADOProfile + ".\n"
+	}
+	return &adoerrors.ConfigError{Err: fmt.Errorf("%s", msg)}
+}
+
+// FilePath returns the path of the config file: $ADOWORK_CONFIG, or
+// $XDG_CONFIG_HOME/adowork/config.yaml.
+func FilePath() (string, error) {
+	if p := os.Getenv(EnvConfigFile); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "adowork", "config.yaml"), nil
+}
+
+// ReadFile reads the config file. A missing file yields an empty File.
+func ReadFile(path string) (File, error) {
+	var file File
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+	if err != nil {
+		return file, &adoerrors.ConfigError{Err: fmt.Errorf("Error reading config file: %w", err)}
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return file, &adoerrors.ConfigError{Err: fmt.Errorf("Error parsing config file '%s': %w", path, err)}
+	}
+	return file, nil
+}
+
+// WriteFile writes the config file, creating its directory if needed.
+// The file may reference credentials, so it is only readable by the owner.
+func WriteFile(path string, file File) error {
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("Error encoding config file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("Error creating config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("Error writing config file: %w", err)
+	}
+	return nil
+}
+
+// selectProfile returns the name of the profile to use: --profile, then ADO_PROFILE, then the
+// file's current-profile, then a profile named "default" if the file defines one.
+func selectProfile(flagProfile string, file File) string {
+	for _, name := range []string{flagProfile, os.Getenv(EnvADOProfile), file.CurrentProfile} {
+		if name != "" {
+			return name
+		}
+	}
+	if _, ok := file.Profiles[DefaultProfile]; ok {
+		return DefaultProfile
+	}
+	return ""
+}
+
+// Resolve merges the config file profile, ADO_* environment variables and flags, in
+// increasing order of precedence. It does not check for missing values or an unknown profile.
+func Resolve(flags Flags) (cfg Config, err error) {
+	cfg.Sources = make(map[string]string)
+
+	cfg.ConfigPath, err = FilePath()
+	if err != nil {
+		return cfg, err
+	}
+	file, err := ReadFile(cfg.ConfigPath)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg.Profile = selectProfile(flags.Profile, file)
+	if cfg.Profile != "" {
+		// An unknown profile is reported by CheckMissing, so that `config set` can still create it.
+		profile, ok := file.Profiles[cfg.Profile]
+		cfg.profileMissing = !ok
+		cfg.set(KeyOrganization, &cfg.Organization, profile.Organization, SourceFile)
+		cfg.set(KeyProject, &cfg.Project, profile.Project, SourceFile)
+		cfg.set(KeyBaseURL, &cfg.BaseURL, profile.BaseURL, SourceFile)
+		cfg.setCredential(KeyPAT, profile.Credential.provider(), SourceFile)
+		cfg.setCredential(KeyToken, profile.Token.provider(), SourceFile)
+		cfg.Defaults = profile.Defaults
+		cfg.Credential = profile.Credential
+		cfg.TokenSource = profile.Token
+	}
+
+	cfg.set(KeyOrganization, &cfg.Organization, os.Getenv(EnvADOOrg), SourceEnv)
+	cfg.set(KeyProject, &cfg.Project, os.Getenv(EnvADOProject), SourceEnv)
+	cfg.set(KeyBaseURL, &cfg.BaseURL, os.Getenv(EnvADOBaseURL), SourceEnv)
+	// Later calls win: a token over a PAT, and a value over a file or command.
+	if path := os.Getenv(EnvADOPATFile); path != "" {
+		cfg.setCredential(KeyPAT, FileCredential{Path: path}, SourceEnv)
+	}
+	cfg.setCredential(KeyPAT, EnvCredential{Name: EnvADOPAT}, SourceEnv)
+	if command := os.Getenv(EnvADOTokenCommand); command != "" {
+		cfg.setCredential(KeyToken, TokenCommandCredential{Command: command}, SourceEnv)
+	}
+	if path := os.Getenv(EnvADOTokenFile); path != "" {
+		cfg.setCredential(KeyToken, FileCredential{Path: path}, SourceEnv)
+	}
+	cfg.setCredential(KeyToken, EnvCredential{Name: EnvADOToken}, SourceEnv)
+
+	cfg.set(KeyOrganization, &cfg.Organization, flags.Organization, SourceFlag)
+	cfg.set(KeyProject, &cfg.Project, flags.Project, SourceFlag)
+	cfg.set(KeyBaseURL, &cfg.BaseURL, flags.BaseURL, SourceFlag)
+	if flags.PATStdin {
+		cfg.setCredential(KeyPAT, StdinCredential{}, SourceFlag)
+	}
+
+	if cfg.BaseURL == "" {
+		cfg.Sources[KeyBaseURL] = SourceDefault
+	}
+	cfg.BaseURL = cfg.normalizeBaseURL()
+
+	return cfg, nil
+}
+
+// Load resolves the configuration and validates that all required values are present.
+func Load(flags Flags) (cfg Config, err error) {
+	cfg, err = Resolve(flags)
+	if err != nil {
+		return
+	}
+	_, err = cfg.CheckMissing()
+	return
+}
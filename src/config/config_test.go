@@ -0,0 +1,173 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate_AllPresent(t *testing.T) {
+	cfg := Config{
+		Organization: "test_org",
+		Project:      "test_proj",
+		PAT:          "test_pat",
+		BaseURL:      "test_url",
+	}
+	_, err := cfg.CheckMissing()
+
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestConfigValidate_MissingFields(t *testing.T) {
+	cfg := Config{}
+	missing, _ := cfg.CheckMissing()
+	want := []string{EnvADOOrg, EnvADOProject, EnvADOPAT, EnvADOBaseURL}
+	if len(missing) != len(want) {
+		t.Errorf("Expected %d missing fields, got %d", len(want), len(missing))
+	}
+	for _, field := range want {
+		found := slices.Contains(missing, field)
+		if !found {
+			t.Errorf("Missing field %q not found in result", field)
+		}
+	}
+}
+
+func TestConfigValidate_SomeMissing(t *testing.T) {
+	cfg := Config{Organization: "org"}
+	missing, _ := cfg.CheckMissing()
+	if len(missing) != 3 {
+		t.Errorf("Expected 3 missing fields, got %d", len(missing))
+	}
+	if !slices.Contains(missing, EnvADOProject) {
+		t.Errorf("Expected missing field %q", EnvADOProject)
+	}
+	if !slices.Contains(missing, EnvADOPAT) {
+		t.Errorf("Expected missing field %q", EnvADOPAT)
+	}
+	if !slices.Contains(missing, EnvADOBaseURL) {
+		t.Errorf("Expected missing field %q", EnvADOBaseURL)
+	}
+}
+
+// writeTestConfigFile writes a config file and points ADOWORK_CONFIG at it.
+func writeTestConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvConfigFile, path)
+	return path
+}
+
+const testConfigFile = `
+current-profile: work
+profiles:
+  work:
+    organization: work-org
+    project: work-project
+    base-url: https://dev.azure.com/
+    credential:
+      env: WORK_PAT
+    defaults:
+      type: Task
+      area: work-project\Team A
+  personal:
+    organization: my-org
+    project: my-project
+`
+
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	for _, env := range []string{EnvADOOrg, EnvADOProject, EnvADOPAT, EnvADOPATFile, EnvADOToken, EnvADOTokenFile, EnvADOTokenCommand, EnvADOBaseURL, EnvADOProfile} {
+		t.Setenv(env, "")
+	}
+}
+
+func TestResolveConfig_ProfileFromFile(t *testing.T) {
+	clearConfigEnv(t)
+	writeTestConfigFile(t, testConfigFile)
+	t.Setenv("WORK_PAT", "file-pat")
+
+	cfg, err := Resolve(Flags{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Profile != "work" || cfg.Organization != "work-org" || cfg.Project != "work-project" {
+		t.Errorf("unexpected profile values: %+v", cfg)
+	}
+	if cfg.BaseURL != "https://dev.azure.com" {
+		t.Errorf("BaseURL: got %q", cfg.BaseURL)
+	}
+	if cfg.PAT != "file-pat" || cfg.Sources[KeyPAT] != SourceFile {
+		t.Errorf("PAT: got %q from %q", cfg.PAT, cfg.Sources[KeyPAT])
+	}
+	if cfg.Defaults.Type != "Task" || cfg.Defaults.Area != `work-project\Team A` {
+		t.Errorf("Defaults: got %+v", cfg.Defaults)
+	}
+}
+
+func TestResolveConfig_Precedence(t *testing.T) {
+	clearConfigEnv(t)
+	writeTestConfigFile(t, testConfigFile)
+	t.Setenv(EnvADOProfile, "personal")
+	t.Setenv(EnvADOProject, "env-project")
+	t.Setenv(EnvADOPAT, "env-pat")
+
+	cfg, err := Resolve(Flags{Project: "flag-project"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		key, got, want, source string
+	}{
+		{KeyOrganization, cfg.Organization, "my-org", SourceFile},
+		{KeyProject, cfg.Project, "flag-project", SourceFlag},
+		{KeyPAT, cfg.PAT, "env-pat", SourceEnv},
+		{KeyBaseURL, cfg.BaseURL, DefaultBaseURL, SourceDefault},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want || cfg.Sources[tt.key] != tt.source {
+			t.Errorf("%s: got %q from %q, want %q from %q", tt.key, tt.got, cfg.Sources[tt.key], tt.want, tt.source)
+		}
+	}
+
+	// --profile takes precedence over ADO_PROFILE
+	cfg, err = Resolve(Flags{Profile: "work"})
+	if err != nil || cfg.Organization != "work-org" {
+		t.Errorf("expected --profile to select 'work', got %q, %v", cfg.Organization, err)
+	}
+}
+
+func TestResolveConfig_UnknownProfile(t *testing.T) {
+	clearConfigEnv(t)
+	writeTestConfigFile(t, testConfigFile)
+
+	cfg, err := Resolve(Flags{Profile: "nope"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cfg.CheckMissing(); err == nil || !strings.Contains(err.Error(), `Profile "nope" not found`) {
+		t.Errorf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestFormatMissingEnvError_ReportsSources(t *testing.T) {
+	err := formatMissingEnvError([]string{EnvADOOrg, EnvADOPAT}, "work", "/tmp/config.yaml")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{
+		"ADO_ORG (expected from env ADO_ORG, flag --org, 'organization' in profile \"work\" of /tmp/config.yaml)",
+		"ADO_PAT (expected from env ADO_PAT, env ADO_PAT_FILE, env ADO_TOKEN, env ADO_TOKEN_FILE, env ADO_TOKEN_COMMAND, flag --pat-stdin, 'credential' or 'token' in profile \"work\" of /tmp/config.yaml)",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got:\n%s", want, err.Error())
+		}
+	}
+}
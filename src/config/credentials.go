@@ -0,0 +1,203 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+const (
+	EnvADOPATFile      string = "ADO_PAT_FILE"
+	EnvADOToken        string = "ADO_TOKEN"
+	EnvADOTokenFile    string = "ADO_TOKEN_FILE"
+	EnvADOTokenCommand string = "ADO_TOKEN_COMMAND"
+)
+
+// CredentialProvider supplies the secret used to authenticate with Azure DevOps.
+// Providers are only asked for the secret when a client is created, so commands that do not
+// talk to Azure DevOps never run a helper or read stdin.
+type CredentialProvider interface {
+	// Credential returns the secret for the given organization URL.
+	Credential(ctx context.Context, organizationURL string) (string, error)
+	// String describes where the secret comes from, without revealing it.
+	String() string
+}
+
+// EnvCredential reads the secret from an environment variable.
+type EnvCredential struct {
+	Name string
+}
+
+// Credential returns the value of the environment variable.
+func (p EnvCredential) Credential(ctx context.Context, organizationURL string) (string, error) {
+	return nonEmptyCredential(os.Getenv(p.Name), p)
+}
+
+// String names the environment variable.
+func (p EnvCredential) String() string {
+	return "env " + p.Name
+}
+
+// FileCredential reads the secret from a file, such as a Docker or Kubernetes secret mount.
+type FileCredential struct {
+	Path string
+}
+
+// Credential returns the content of the file, trimmed.
+func (p FileCredential) Credential(ctx context.Context, organizationURL string) (string, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return "", fmt.Errorf("Error reading credential file: %w", err)
+	}
+	return nonEmptyCredential(string(data), p)
+}
+
+// String names the file.
+func (p FileCredential) String() string {
+	return "file " + p.Path
+}
+
+// StdinCredential reads the secret from the first line of standard input.
+type StdinCredential struct {
+	Reader io.Reader
+}
+
+// Credential returns the first line read from Reader, or from standard input when Reader is nil.
+func (p StdinCredential) Credential(ctx context.Context, organizationURL string) (string, error) {
+	r := p.Reader
+	if r == nil {
+		r = os.Stdin
+	}
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("Error reading credential from stdin: %w", err)
+	}
+	return nonEmptyCredential(line, p)
+}
+
+// String describes standard input as the source.
+func (p StdinCredential) String() string {
+	return "stdin"
+}
+
+// CommandCredential runs an external helper that prints the secret, following the
+// git-credential-helper protocol: the helper is run with the "get" argument and receives
+// protocol, host and path attributes on stdin. It answers with a "password=<secret>" line;
+// a helper that prints only the bare secret is accepted too.
+type CommandCredential struct {
+	Command string
+}
+
+// Credential runs the helper and returns the password it answers with.
+func (p CommandCredential) Credential(ctx context.Context, organizationURL string) (string, error) {
+	cmd := shellCommand(ctx, p.Command+" get")
+	cmd.Stdin = strings.NewReader(credentialRequest(organizationURL))
+	// The helper may prompt or report errors; let it talk to the user directly.
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Credential helper '%s' failed: %w", p.Command, err)
+	}
+	return nonEmptyCredential(parseCredentialResponse(out), p)
+}
+
+// String names the helper command.
+func (p CommandCredential) String() string {
+	return "command " + p.Command
+}
+
+// TokenCommandCredential runs a command that prints an access token, such as
+// `az account get-access-token --resource 499b84ac-1321-427f-aa17-267ca6975798`.
+// The output is either the bare token or a JSON object with an accessToken property.
+type TokenCommandCredential struct {
+	Command string
+}
+
+// Credential runs the command and returns the access token it prints.
+func (p TokenCommandCredential) Credential(ctx context.Context, organizationURL string) (string, error) {
+	cmd := shellCommand(ctx, p.Command)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Token command '%s' failed: %w", p.Command, err)
+	}
+	return nonEmptyCredential(parseTokenResponse(out), p)
+}
+
+// String names the token command.
+func (p TokenCommandCredential) String() string {
+	return "command " + p.Command
+}
+
+// parseTokenResponse returns the accessToken property of JSON output, or the whole output otherwise.
+func parseTokenResponse(out []byte) string {
+	var response struct {
+		AccessToken string `json:"accessToken"`
+	}
+	if err := json.Unmarshal(out, &response); err == nil {
+		return response.AccessToken
+	}
+	return string(bytes.TrimSpace(out))
+}
+
+// shellCommand returns a command that runs line through the system shell, as git does for helpers.
+func shellCommand(ctx context.Context, line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", line)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", line)
+}
+
+// credentialRequest describes the organization URL as git-credential attributes.
+func credentialRequest(organizationURL string) string {
+	var b strings.Builder
+	if u, err := url.Parse(organizationURL); err == nil && u.Host != "" {
+		fmt.Fprintf(&b, "protocol=%s\nhost=%s\n", u.Scheme, u.Host)
+		if path := strings.Trim(u.Path, "/"); path != "" {
+			fmt.Fprintf(&b, "path=%s\n", path)
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// credentialAttributes are the git-credential attributes recognized in a helper response.
+var credentialAttributes = []string{"protocol", "host", "path", "username", "password", "url"}
+
+// parseCredentialResponse returns the password attribute of a helper response, or the whole
+// output when the helper did not answer with attributes.
+func parseCredentialResponse(out []byte) string {
+	sawAttribute := false
+	for _, line := range strings.Split(string(out), "\n") {
+		key, value, ok := strings.Cut(strings.TrimRight(line, "\r"), "=")
+		if !ok || !slices.Contains(credentialAttributes, key) {
+			continue
+		}
+		sawAttribute = true
+		if key == "password" {
+			return value
+		}
+	}
+	if sawAttribute {
+		return ""
+	}
+	return string(bytes.TrimSpace(out))
+}
+
+// nonEmptyCredential trims the secret and reports an error if nothing is left.
+func nonEmptyCredential(secret string, p CredentialProvider) (string, error) {
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return "", fmt.Errorf("No credential found in %s", p)
+	}
+	return secret, nil
+}
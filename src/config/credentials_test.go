@@ -0,0 +1,170 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFileCredential(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pat")
+	if err := os.WriteFile(path, []byte("file-pat\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pat, err := FileCredential{Path: path}.Credential(context.Background(), "")
+	if err != nil || pat != "file-pat" {
+		t.Errorf("got %q, %v", pat, err)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	os.WriteFile(empty, nil, 0o600)
+	if _, err := (FileCredential{Path: empty}).Credential(context.Background(), ""); err == nil {
+		t.Errorf("expected error for an empty credential file")
+	}
+}
+
+func TestStdinCredential_ReadsFirstLine(t *testing.T) {
+	pat, err := StdinCredential{Reader: strings.NewReader("stdin-pat\nmore input\n")}.Credential(context.Background(), "")
+	if err != nil || pat != "stdin-pat" {
+		t.Errorf("got %q, %v", pat, err)
+	}
+}
+
+func TestCommandCredential_GitCredentialProtocol(t *testing.T) {
+	dir := t.TempDir()
+	request := filepath.Join(dir, "request")
+	helper := filepath.Join(dir, "helper.sh")
+	script := "#!/bin/sh\n[ \"$1\" = get ] || exit 1\ncat > " + request + "\necho username=pat\necho password=helper-pat\n"
+	if err := os.WriteFile(helper, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	pat, err := CommandCredential{Command: helper}.Credential(context.Background(), "https://dev.azure.com/my-org")
+	if err != nil || pat != "helper-pat" {
+		t.Fatalf("got %q, %v", pat, err)
+	}
+	got, _ := os.ReadFile(request)
+	if want := "protocol=https\nhost=dev.azure.com\npath=my-org\n\n"; string(got) != want {
+		t.Errorf("helper request: got %q, want %q", got, want)
+	}
+}
+
+func TestCommandCredential_Failure(t *testing.T) {
+	_, err := CommandCredential{Command: "exit 3; true"}.Credential(context.Background(), "https://dev.azure.com/my-org")
+	if err == nil || !strings.Contains(err.Error(), "Credential helper") {
+		t.Errorf("expected helper error, got %v", err)
+	}
+}
+
+func TestParseCredentialResponse(t *testing.T) {
+	tests := map[string]string{
+		"password=secret\n":          "secret",
+		"username=x\npassword=a=b\n": "a=b",
+		"bare-token\n":               "bare-token",
+		"abc==\n":                    "abc==",
+		"username=x\n":               "",
+	}
+	for out, want := range tests {
+		if got := parseCredentialResponse([]byte(out)); got != want {
+			t.Errorf("parseCredentialResponse(%q) = %q, want %q", out, got, want)
+		}
+	}
+}
+
+func TestResolveConfig_CredentialPrecedence(t *testing.T) {
+	clearConfigEnv(t)
+	writeTestConfigFile(t, `
+profiles:
+  default:
+    credential:
+      command: vault-helper
+`)
+
+	cfg, err := Resolve(Flags{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Credentials != (CommandCredential{Command: "vault-helper"}) || cfg.Sources[KeyPAT] != SourceFile {
+		t.Errorf("expected the profile helper, got %v from %q", cfg.Credentials, cfg.Sources[KeyPAT])
+	}
+	if missing, _ := cfg.CheckMissing(); slices.Contains(missing, EnvADOPAT) {
+		t.Errorf("a credential provider should satisfy the PAT requirement, missing: %v", missing)
+	}
+
+	t.Setenv(EnvADOPATFile, "/run/secrets/ado-pat")
+	cfg, _ = Resolve(Flags{})
+	if cfg.Credentials != (FileCredential{Path: "/run/secrets/ado-pat"}) || cfg.Sources[KeyPAT] != SourceEnv {
+		t.Errorf("expected ADO_PAT_FILE to override the profile, got %v", cfg.Credentials)
+	}
+
+	t.Setenv(EnvADOPAT, "env-pat")
+	cfg, _ = Resolve(Flags{})
+	if cfg.PAT != "env-pat" {
+		t.Errorf("expected ADO_PAT to take precedence, got %v", cfg.Credentials)
+	}
+
+	cfg, _ = Resolve(Flags{PATStdin: true})
+	if cfg.PAT != "" || cfg.Credentials != (StdinCredential{}) || cfg.Sources[KeyPAT] != SourceFlag {
+		t.Errorf("expected --pat-stdin to take precedence, got %v", cfg.Credentials)
+	}
+}
+
+func TestTokenCommandCredential(t *testing.T) {
+	tests := map[string]string{
+		"echo bare-token": "bare-token",
+		`echo '{"accessToken": "json-token", "expiresOn": "x"}'`: "json-token",
+	}
+	for command, want := range tests {
+		token, err := TokenCommandCredential{Command: command}.Credential(context.Background(), "")
+		if err != nil || token != want {
+			t.Errorf("%s: got %q, %v; want %q", command, token, err, want)
+		}
+	}
+}
+
+func TestResolveConfig_TokenOverPAT(t *testing.T) {
+	clearConfigEnv(t)
+	writeTestConfigFile(t, `
+profiles:
+  default:
+    credential:
+      env: WORK_PAT
+    token:
+      command: az account get-access-token
+`)
+	t.Setenv("WORK_PAT", "file-pat")
+
+	cfg, err := Resolve(Flags{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.UsesToken() || cfg.PAT != "" || cfg.Sources[KeyToken] != SourceFile {
+		t.Errorf("expected the profile token to take precedence over its PAT, got %+v", cfg)
+	}
+
+	t.Setenv(EnvADOPAT, "env-pat")
+	cfg, _ = Resolve(Flags{})
+	if cfg.UsesToken() || cfg.PAT != "env-pat" || cfg.Sources[KeyPAT] != SourceEnv || cfg.Sources[KeyToken] != "" {
+		t.Errorf("expected ADO_PAT to override the profile token, got %+v", cfg)
+	}
+
+	t.Setenv(EnvADOToken, "env-token")
+	cfg, _ = Resolve(Flags{})
+	secret, err := cfg.ResolveCredential(context.Background())
+	if !cfg.UsesToken() || err != nil || secret != "env-token" {
+		t.Errorf("expected ADO_TOKEN to be used, got %q, %v", secret, err)
+	}
+}
+
+func TestCheckMissing_AcceptsToken(t *testing.T) {
+	cfg := Config{Organization: "org", Project: "proj", Token: "token", BaseURL: DefaultBaseURL}
+	if missing, err := cfg.CheckMissing(); err != nil {
+		t.Errorf("expected a token to satisfy the credential requirement, missing: %v", missing)
+	}
+	if !cfg.UsesToken() {
+		t.Errorf("expected bearer authentication")
+	}
+}
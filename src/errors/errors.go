@@ -0,0 +1,202 @@
+// Package errors classifies the errors returned by the Azure DevOps Go SDK and by adowork, so that
+// callers can tell authentication, network, validation, rate limit, conflict, malformed response,
+// configuration and usage failures apart.
+//
+// Each classifier returns true if the error, or any error it wraps, matches the category.
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net"
+
+	// Azure DevOps Go SDK error types
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+)
+
+// IsAuthError returns true if the error is an authentication error (e.g., 401/403, invalid PAT).
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	status, ok := StatusCode(err)
+	return ok && (status == 401 || status == 403)
+}
+
+// IsNetworkError returns true if the error is a network error (timeouts, DNS, connection reset).
+func IsNetworkError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if stderrors.As(err, &netErr) {
+		return true
+	}
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return false
+}
+
+// IsValidationError returns true if the error is a validation error (HTTP 400/422, field validation).
+func IsValidationError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if stderrors.As(err, new(*azuredevops.InvalidVersionStringError)) {
+		return true
+	}
+	if stderrors.As(err, new(azuredevops.InvalidApiVersion)) {
+		return true
+	}
+	if stderrors.As(err, new(azuredevops.LocationIdNotRegisteredError)) {
+		return true
+	}
+	status, ok := StatusCode(err)
+	return ok && (status == 400 || status == 422)
+}
+
+// IsRateLimitError returns true if the error is a rate limiting error (HTTP 429, rate limit messages).
+func IsRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	status, ok := StatusCode(err)
+	return ok && status == 429
+}
+
+// IsConflictError returns true if the error is a concurrency conflict (HTTP 409/412, e.g. a failed /rev test operation).
+func IsConflictError(err error) bool {
+	if err == nil {
+		return false
+	}
+	status, ok := StatusCode(err)
+	return ok && (status == 409 || status == 412)
+}
+
+// IsMalformedResponseError returns true if the error is a malformed response error (JSON unmarshal, unexpected format).
+func IsMalformedResponseError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if stderrors.As(err, &syntaxErr) || stderrors.As(err, &typeErr) {
+		return true
+	}
+	return false
+}
+
+// ConfigError marks an error in the configuration: a missing setting, an unknown profile or an
+// unreadable config file.
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string { return e.Err.Error() }
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// IsConfigError returns true if the error comes from missing or invalid configuration.
+func IsConfigError(err error) bool {
+	var ce *ConfigError
+	return stderrors.As(err, &ce)
+}
+
+// UsageError marks invalid input given to the command line: an unknown flag, a flag value that
+// fails validation, or a work item type, field or input file that cannot be used.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+func (e *UsageError) Unwrap() error { return e.Err }
+
+// IsUsageError returns true if the error comes from invalid command-line input.
+func IsUsageError(err error) bool {
+	var ue *UsageError
+	return stderrors.As(err, &ue)
+}
+
+// OperationError records the operation that failed, such as "creating work item", for error reports.
+type OperationError struct {
+	Operation string
+	Err       error
+}
+
+func (e *OperationError) Error() string { return e.Err.Error() }
+func (e *OperationError) Unwrap() error { return e.Err }
+
+// IsArgumentError checks if an error is an argument validation error from the Azure DevOps library
+func IsArgumentError(err error) bool {
+	var argNilErr *azuredevops.ArgumentNilError
+	var argNilOrEmptyErr *azuredevops.ArgumentNilOrEmptyError
+	return stderrors.As(err, &argNilErr) || stderrors.As(err, &argNilOrEmptyErr)
+}
+
+// IsAPIError checks if an error is an API error from the Azure DevOps library
+func IsAPIError(err error) bool {
+	var wrappedErr *azuredevops.WrappedError
+	return stderrors.As(err, &wrappedErr)
+}
+
+// GetAPIErrorDetails extracts detailed error information from Azure DevOps API errors
+func GetAPIErrorDetails(err error) (statusCode int, message string, details map[string]interface{}) {
+	var wrappedErr *azuredevops.WrappedError
+	if stderrors.As(err, &wrappedErr) {
+		if wrappedErr.StatusCode != nil {
+			statusCode = *wrappedErr.StatusCode
+		}
+		if wrappedErr.Message != nil {
+			message = *wrappedErr.Message
+		}
+		if wrappedErr.CustomProperties != nil {
+			details = *wrappedErr.CustomProperties
+		}
+		return statusCode, message, details
+	}
+	return 0, "", nil
+}
+
+// FormatADOError provides a user-friendly error message with context from Azure DevOps API errors
+func FormatADOError(err error, operation string) error {
+	if IsArgumentError(err) {
+		return &OperationError{Operation: operation, Err: fmt.Errorf("%s failed due to invalid arguments: %w", operation, err)}
+	}
+
+	if IsAPIError(err) {
+		statusCode, message, _ := GetAPIErrorDetails(err)
+		// WrappedError renders as its message, so wrapping keeps the text while preserving the error type
+		if statusCode != 0 && message != "" {
+			return &OperationError{Operation: operation, Err: fmt.Errorf("%s failed (HTTP %d): %w", operation, statusCode, err)}
+		}
+	}
+
+	return &OperationError{Operation: operation, Err: fmt.Errorf("%s failed: %w", operation, err)}
+}
+
+// StatusCode returns the HTTP status code of an Azure DevOps API error.
+func StatusCode(err error) (int, bool) {
+	if we := APIError(err); we != nil && we.StatusCode != nil {
+		return *we.StatusCode, true
+	}
+	return 0, false
+}
+
+// APIError returns the Azure DevOps API error in the chain, or nil.
+// The SDK returns WrappedError both by value and by pointer, so both are checked.
+func APIError(err error) *azuredevops.WrappedError {
+	var we azuredevops.WrappedError
+	if stderrors.As(err, &we) {
+		return &we
+	}
+	var wep *azuredevops.WrappedError
+	if stderrors.As(err, &wep) {
+		return wep
+	}
+	return nil
+}
+
+// All error classification helpers use type-based checks.
+// When Azure DevOps SDK error types are updated, revisit type assertions as needed.
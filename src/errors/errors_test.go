@@ -0,0 +1,222 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+)
+
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		// All string-based cases should now return false
+		{"401 string", stderrors.New("401 Unauthorized"), false},
+		{"403 string", stderrors.New("403 Forbidden"), false},
+		{"unauthorized msg", stderrors.New("user unauthorized"), false},
+		{"forbidden msg", stderrors.New("access forbidden"), false},
+		{"unrelated", stderrors.New("some other error"), false},
+		{"wrapped unauthorized", fmt.Errorf("wrap: %w", stderrors.New("401 Unauthorized")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthError(tt.err); got != tt.want {
+				t.Errorf("IsAuthError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsNetworkError(t *testing.T) {
+	timeoutErr := &net.DNSError{IsTimeout: true}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"timeout net.Error", timeoutErr, true},
+		{"deadline exceeded", context.DeadlineExceeded, true},
+		// All string-based cases should now return false
+		{"connection refused", stderrors.New("connection refused"), false},
+		{"timeout string", stderrors.New("timeout occurred"), false},
+		{"network unreachable", stderrors.New("network unreachable"), false},
+		{"unrelated", stderrors.New("some other error"), false},
+		{"wrapped timeout", fmt.Errorf("wrap: %w", stderrors.New("timeout occurred")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNetworkError(tt.err); got != tt.want {
+				t.Errorf("IsNetworkError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidationError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		// All string-based cases should now return false
+		{"400 bad request", stderrors.New("400 Bad Request"), false},
+		{"422 validation", stderrors.New("422 validation error"), false},
+		{"validation msg", stderrors.New("validation failed"), false},
+		{"bad request msg", stderrors.New("bad request"), false},
+		{"unrelated", stderrors.New("some other error"), false},
+		{"wrapped validation", fmt.Errorf("wrap: %w", stderrors.New("validation failed")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidationError(tt.err); got != tt.want {
+				t.Errorf("IsValidationError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		// All string-based cases should now return false
+		{"429 too many requests", stderrors.New("429 Too Many Requests"), false},
+		{"rate limit msg", stderrors.New("rate limit exceeded"), false},
+		{"too many requests msg", stderrors.New("too many requests"), false},
+		{"unrelated", stderrors.New("some other error"), false},
+		{"wrapped rate limit", fmt.Errorf("wrap: %w", stderrors.New("rate limit exceeded")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRateLimitError(tt.err); got != tt.want {
+				t.Errorf("IsRateLimitError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMalformedResponseError(t *testing.T) {
+	syntaxErr := &json.SyntaxError{}
+	typeErr := &json.UnmarshalTypeError{}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"syntax error", syntaxErr, true},
+		{"unmarshal type error", typeErr, true},
+		// All string-based cases should now return false
+		{"invalid character", stderrors.New("invalid character '}' looking for beginning of object key string"), false},
+		{"unexpected end of json", stderrors.New("unexpected end of JSON input"), false},
+		{"unmarshal msg", stderrors.New("json: cannot unmarshal string into Go value"), false},
+		{"unrelated", stderrors.New("some other error"), false},
+		{"wrapped unmarshal", fmt.Errorf("wrap: %w", stderrors.New("json: cannot unmarshal string into Go value")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMalformedResponseError(tt.err); got != tt.want {
+				t.Errorf("IsMalformedResponseError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func makeWrappedError(status int) error {
+	code := status
+	msg := "mock error"
+	return azuredevops.WrappedError{StatusCode: &code, Message: &msg}
+}
+
+func TestIsAuthError_TypeBased(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"401 WrappedError", makeWrappedError(401), true},
+		{"403 WrappedError", makeWrappedError(403), true},
+		{"404 WrappedError", makeWrappedError(404), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthError(tt.err); got != tt.want {
+				t.Errorf("IsAuthError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidationError_TypeBased(t *testing.T) {
+	verr := &azuredevops.InvalidVersionStringError{}
+	apiErr := azuredevops.InvalidApiVersion{}
+	locErr := azuredevops.LocationIdNotRegisteredError{}
+	badReq := makeWrappedError(400)
+	val422 := makeWrappedError(422)
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"InvalidVersionStringError", verr, true},
+		{"InvalidApiVersion", apiErr, true},
+		{"LocationIdNotRegisteredError", locErr, true},
+		{"400 WrappedError", badReq, true},
+		{"422 WrappedError", val422, true},
+		{"404 WrappedError", makeWrappedError(404), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidationError(tt.err); got != tt.want {
+				t.Errorf("IsValidationError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRateLimitError_TypeBased(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"429 WrappedError", makeWrappedError(429), true},
+		{"401 WrappedError", makeWrappedError(401), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRateLimitError(tt.err); got != tt.want {
+				t.Errorf("IsRateLimitError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatError ensures FormatError produces the expected error message.
+func TestFormatError(t *testing.T) {
+	wrapped := FormatADOError(stderrors.New("API call failed"), "creating work item")
+	actual := wrapped.Error()
+	if !strings.Contains(actual, "API call failed") {
+		t.Errorf("Expected error message to contain 'API call failed', got '%s'", actual)
+	}
+	if !strings.Contains(actual, "creating work item failed") {
+		t.Errorf("Expected error message to contain 'creating work item failed', got '%s'", actual)
+	}
+}
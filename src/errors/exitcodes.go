@@ -0,0 +1,28 @@
+package errors
+
+// Exit codes of the adowork command, one per error class, so that scripts and wrappers can tell
+// failures apart. They are part of the command-line interface and do not change between releases.
+const (
+	// ExitOK is returned when the command succeeds.
+	ExitOK = 0
+	// ExitError is returned for errors that fit no other class.
+	ExitError = 1
+	// ExitConfig is returned when required settings are missing, the profile is unknown or the
+	// config file cannot be read.
+	ExitConfig = 2
+	// ExitValidation is returned when Azure DevOps rejects the input (HTTP 400/422).
+	ExitValidation = 3
+	// ExitAuth is returned when the PAT or access token is invalid, expired or lacks permissions (HTTP 401/403).
+	ExitAuth = 4
+	// ExitNetwork is returned when Azure DevOps cannot be reached.
+	ExitNetwork = 5
+	// ExitRateLimit is returned when Azure DevOps still throttles the requests after the retries (HTTP 429).
+	ExitRateLimit = 6
+	// ExitConflict is returned when the work item changed since the expected revision (HTTP 409/412).
+	ExitConflict = 7
+	// ExitMalformedResponse is returned when Azure DevOps sends data that cannot be decoded.
+	ExitMalformedResponse = 8
+	// ExitUsage is returned when the command-line input is invalid: an unknown flag, a flag value that
+	// fails validation, an unknown work item type or field, or an input file that cannot be used.
+	ExitUsage = 9
+)
@@ -0,0 +1,165 @@
+package errors
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// FieldError describes why Azure DevOps rejected the value of one field.
+type FieldError struct {
+	// Field is the reference name of the field, or its display name when it could not be resolved.
+	Field         string      `json:"field"`
+	Name          string      `json:"name,omitempty"`
+	Value         interface{} `json:"value,omitempty"`
+	Reason        string      `json:"reason,omitempty"`
+	AllowedValues []string    `json:"allowedValues,omitempty"`
+}
+
+// String renders the field error on one line.
+func (f FieldError) String() string {
+	label := f.Field
+	if f.Name != "" && !strings.EqualFold(f.Name, f.Field) {
+		label = fmt.Sprintf("%s (%s)", f.Name, f.Field)
+	}
+	parts := []string{label + ":"}
+	if f.Reason != "" {
+		parts = append(parts, f.Reason+".")
+	}
+	if f.Value != nil {
+		parts = append(parts, fmt.Sprintf("Value: '%v'.", f.Value))
+	}
+	if len(f.AllowedValues) > 0 {
+		parts = append(parts, "Allowed values: "+strings.Join(f.AllowedValues, ", ")+".")
+	}
+	return strings.Join(parts, " ")
+}
+
+// FieldValidationError is a validation error along with the fields that Azure DevOps rejected.
+type FieldValidationError struct {
+	Fields []FieldError
+	Err    error
+}
+
+func (e *FieldValidationError) Error() string { return e.Err.Error() }
+func (e *FieldValidationError) Unwrap() error { return e.Err }
+
+var (
+	// ruleErrorPattern matches e.g. "TF401320: Rule Error for field Priority. Error code: Required, InvalidEmpty."
+	ruleErrorPattern = regexp.MustCompile(`Rule Error for field ([^.]+)\. Error code: ([^.]+)`)
+	// fieldStatusPattern matches e.g. "TF401326: Invalid field status 'InvalidListValue' for field 'Microsoft.VSTS.Common.Priority'."
+	fieldStatusPattern = regexp.MustCompile(`Invalid field status '([^']+)' for field '([^']+)'`)
+	// invalidValuePattern matches e.g. "The field 'Priority' contains the value '7' that is not in the list of supported values".
+	invalidValuePattern = regexp.MustCompile(`The field '([^']+)' contains the value '([^']*)' that is not in the list of supported values`)
+)
+
+// fieldStatusReasons describes the field status flags that Azure DevOps reports for rejected values.
+var fieldStatusReasons = map[string]string{
+	"required":             "a value is required",
+	"invalidempty":         "a value is required",
+	"invalidlistvalue":     "the value is not one of the allowed values",
+	"limitedtovalues":      "the value is not one of the allowed values",
+	"invalidnotempty":      "the field must be empty",
+	"invalidnotoldvalue":   "the field cannot keep its current value",
+	"invalidformat":        "the value has an invalid format",
+	"invalidtype":          "the value has the wrong type",
+	"invalidpath":          "the path does not exist",
+	"invalidtoolong":       "the value is too long",
+	"readonly":             "the field is read-only",
+	"invalidcomputedfield": "the field is computed and cannot be set",
+	"invalidunknown":       "the value is invalid",
+}
+
+// ignoredFieldStatuses are field status flags that describe the field rather than the value.
+var ignoredFieldStatuses = []string{"", "none", "hasvalues", "allowsoldvalue", "setbyrule"}
+
+// describeFieldStatus turns field status flags such as "Required, InvalidEmpty" into a description.
+// Flags without a description are kept as they are.
+func describeFieldStatus(flags string) string {
+	var reasons []string
+	for _, flag := range strings.Split(flags, ",") {
+		flag = strings.TrimSpace(flag)
+		if slices.Contains(ignoredFieldStatuses, strings.ToLower(flag)) {
+			continue
+		}
+		reason, ok := fieldStatusReasons[strings.ToLower(flag)]
+		if !ok {
+			reason = flag
+		}
+		if !slices.Contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// ParseFieldErrors extracts the rejected fields from an Azure DevOps validation error, using its
+// custom properties when present and its message otherwise.
+func ParseFieldErrors(err error) []FieldError {
+	we := APIError(err)
+	if we == nil {
+		return nil
+	}
+	var fields []FieldError
+	if we.CustomProperties != nil {
+		props := *we.CustomProperties
+		if list, ok := property(props, "RuleValidationErrors").([]interface{}); ok {
+			for _, item := range list {
+				if m, ok := item.(map[string]interface{}); ok {
+					if f, ok := fieldErrorFromProperties(m); ok {
+						fields = append(fields, f)
+					}
+				}
+			}
+		}
+		if len(fields) == 0 {
+			if f, ok := fieldErrorFromProperties(props); ok {
+				fields = append(fields, f)
+			}
+		}
+	}
+	if len(fields) > 0 || we.Message == nil {
+		return fields
+	}
+
+	message := *we.Message
+	for _, m := range ruleErrorPattern.FindAllStringSubmatch(message, -1) {
+		fields = append(fields, FieldError{Field: strings.TrimSpace(m[1]), Reason: describeFieldStatus(m[2])})
+	}
+	for _, m := range fieldStatusPattern.FindAllStringSubmatch(message, -1) {
+		fields = append(fields, FieldError{Field: m[2], Reason: describeFieldStatus(m[1])})
+	}
+	for _, m := range invalidValuePattern.FindAllStringSubmatch(message, -1) {
+		fields = append(fields, FieldError{Field: m[1], Value: m[2], Reason: describeFieldStatus("InvalidListValue")})
+	}
+	return fields
+}
+
+// fieldErrorFromProperties builds a field error from properties such as FieldReferenceName,
+// FieldStatusFlags, ErrorMessage and InvalidValue.
+func fieldErrorFromProperties(props map[string]interface{}) (FieldError, bool) {
+	ref, _ := property(props, "FieldReferenceName").(string)
+	if ref == "" {
+		return FieldError{}, false
+	}
+	f := FieldError{Field: ref, Value: property(props, "InvalidValue")}
+	if flags, ok := property(props, "FieldStatusFlags").(string); ok {
+		f.Reason = describeFieldStatus(flags)
+	}
+	if f.Reason == "" {
+		f.Reason, _ = property(props, "ErrorMessage").(string)
+	}
+	return f, true
+}
+
+// property returns the value of a property, matching its name case-insensitively: the properties
+// of rule validation errors are camel-cased, those of the error itself are not.
+func property(props map[string]interface{}, name string) interface{} {
+	for k, v := range props {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package errors
+
+import (
+	stderrors "errors"
+	"reflect"
+	"testing"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+)
+
+func makeValidationError(message string, properties map[string]interface{}) *azuredevops.WrappedError {
+	status := 400
+	err := &azuredevops.WrappedError{StatusCode: &status, Message: &message}
+	if properties != nil {
+		err.CustomProperties = &properties
+	}
+	return err
+}
+
+func TestParseFieldErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []FieldError
+	}{
+		{
+			"rule error message",
+			makeValidationError("TF401320: Rule Error for field Priority. Error code: Required, HasValues, LimitedToValues, AllowsOldValue, InvalidEmpty.", nil),
+			[]FieldError{{Field: "Priority", Reason: "a value is required; the value is not one of the allowed values"}},
+		},
+		{
+			"field status message",
+			makeValidationError("TF401326: Invalid field status 'InvalidListValue' for field 'Microsoft.VSTS.Common.Severity'.", nil),
+			[]FieldError{{Field: "Microsoft.VSTS.Common.Severity", Reason: "the value is not one of the allowed values"}},
+		},
+		{
+			"unsupported value message",
+			makeValidationError("The field 'State' contains the value 'Doing' that is not in the list of supported values", nil),
+			[]FieldError{{Field: "State", Value: "Doing", Reason: "the value is not one of the allowed values"}},
+		},
+		{
+			"custom properties",
+			makeValidationError("TF401320: Rule Error for field Priority.", map[string]interface{}{
+				"FieldReferenceName": "Microsoft.VSTS.Common.Priority",
+				"FieldStatusFlags":   "required, invalidEmpty",
+				"InvalidValue":       "",
+			}),
+			[]FieldError{{Field: "Microsoft.VSTS.Common.Priority", Value: "", Reason: "a value is required"}},
+		},
+		{
+			"rule validation errors",
+			makeValidationError("TF401347: Invalid tree name given for work item -1, field 'System.AreaPath'.", map[string]interface{}{
+				"RuleValidationErrors": []interface{}{
+					map[string]interface{}{"fieldReferenceName": "System.AreaPath", "fieldStatusFlags": "none", "errorMessage": "Invalid tree name given"},
+					map[string]interface{}{"fieldReferenceName": "System.Title", "fieldStatusFlags": "required, invalidEmpty"},
+				},
+			}),
+			[]FieldError{
+				{Field: "System.AreaPath", Reason: "Invalid tree name given"},
+				{Field: "System.Title", Reason: "a value is required"},
+			},
+		},
+		{"not an API error", stderrors.New("boom"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseFieldErrors(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFieldErrors() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
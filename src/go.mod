@@ -3,8 +3,18 @@ module github.com/andreswebs/adowork
 go 1.24.5
 
 require (
+	github.com/google/uuid v1.1.1
+	github.com/microcosm-cc/bluemonday v1.0.27
 	github.com/microsoft/azure-devops-go-api/azuredevops v1.0.0-b5
 	github.com/urfave/cli/v3 v3.3.8
+	github.com/yuin/goldmark v1.8.2
+	golang.org/x/term v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require github.com/google/uuid v1.1.1 // indirect
+require (
+	github.com/aymerick/douceur v0.2.0 // indirect
+	github.com/gorilla/css v1.0.1 // indirect
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
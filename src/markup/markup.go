@@ -0,0 +1,80 @@
+// Package markup converts the text given for HTML work item fields, such as the description, to
+// the HTML that Azure DevOps stores.
+package markup
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// Formats of the text given for HTML fields.
+const (
+	// FormatMarkdown is CommonMark with the GitHub extensions: tables, task lists, strikethrough
+	// and autolinks. Line breaks are kept.
+	FormatMarkdown string = "markdown"
+	// FormatHTML is sent as is.
+	FormatHTML string = "html"
+	// FormatText is escaped, with line breaks kept.
+	FormatText string = "text"
+)
+
+// DefaultFormat is used when no format is given. HTML keeps text as it was sent before formats existed.
+const DefaultFormat = FormatHTML
+
+// Formats lists the supported formats.
+var Formats = []string{FormatMarkdown, FormatHTML, FormatText}
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	// Raw HTML is rendered, then cleaned up by the sanitizer along with the rest.
+	goldmark.WithRendererOptions(goldmarkhtml.WithHardWraps(), goldmarkhtml.WithUnsafe()),
+)
+
+// policy allows the HTML of user-generated content, plus the checkboxes of task lists.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}()
+
+// CheckFormat returns an error unless format is one of Formats. An empty format is valid and
+// stands for DefaultFormat.
+func CheckFormat(format string) error {
+	if format == "" {
+		return nil
+	}
+	for _, f := range Formats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid description format: '%s'. Use %s, %s or %s.", format, FormatMarkdown, FormatHTML, FormatText)
+}
+
+// ToHTML converts text in the given format to HTML. Markdown is converted to sanitized HTML.
+func ToHTML(text, format string) (string, error) {
+	if err := CheckFormat(format); err != nil {
+		return "", err
+	}
+	switch format {
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(text), &buf); err != nil {
+			return "", fmt.Errorf("Error converting Markdown: %w", err)
+		}
+		return strings.TrimSpace(policy.Sanitize(buf.String())), nil
+	case FormatText:
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+		return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>"), nil
+	}
+	return text, nil
+}
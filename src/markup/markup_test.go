@@ -0,0 +1,42 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		format string
+		want   string
+	}{
+		{"html as is", "<p>hi</p>\n<script>x</script>", FormatHTML, "<p>hi</p>\n<script>x</script>"},
+		{"default format", "<b>hi</b>", "", "<b>hi</b>"},
+		{"text", "a < b\r\nc & d", FormatText, "a &lt; b<br>c &amp; d"},
+		{"markdown line breaks", "Steps:\nopen\nsave", FormatMarkdown, "<p>Steps:<br>\nopen<br>\nsave</p>"},
+		{"markdown task list", "- [x] done\n- [ ] todo", FormatMarkdown,
+			"<ul>\n<li><input checked=\"\" disabled=\"\" type=\"checkbox\"> done</li>\n<li><input disabled=\"\" type=\"checkbox\"> todo</li>\n</ul>"},
+		{"markdown table", "| a | b |\n|---|---|\n| 1 | 2 |", FormatMarkdown,
+			"<table>\n<thead>\n<tr>\n<th>a</th>\n<th>b</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>1</td>\n<td>2</td>\n</tr>\n</tbody>\n</table>"},
+		{"markdown code block", "```\nx := <1>\n```", FormatMarkdown, "<pre><code>x := &lt;1&gt;\n</code></pre>"},
+		{"markdown sanitized", "hi <img src=x onerror=alert(1)> [link](javascript:alert(1))", FormatMarkdown, "<p>hi <img src=\"x\"> link</p>"},
+		{"empty markdown", "", FormatMarkdown, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToHTML(tt.text, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ToHTML(%q, %q) = %q, want %q", tt.text, tt.format, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ToHTML("hi", "rtf"); err == nil || !strings.Contains(err.Error(), "Use markdown, html or text.") {
+		t.Errorf("expected an invalid format error, got %v", err)
+	}
+}
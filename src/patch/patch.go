@@ -0,0 +1,234 @@
+// Package patch builds the JSON patch documents that create and update Azure DevOps work items.
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+)
+
+// Reference names of the standard fields set by the Builder shortcuts.
+const (
+	FieldTitle         string = "System.Title"
+	FieldDescription   string = "System.Description"
+	FieldAssignedTo    string = "System.AssignedTo"
+	FieldAreaPath      string = "System.AreaPath"
+	FieldIterationPath string = "System.IterationPath"
+	FieldTags          string = "System.Tags"
+)
+
+// Link types of the work item hierarchy, as used in relations.
+const (
+	RelParent string = "System.LinkTypes.Hierarchy-Reverse"
+	RelChild  string = "System.LinkTypes.Hierarchy-Forward"
+)
+
+// Builder builds a patch document one operation at a time:
+//
+//	patchDoc, err := patch.New().
+//		Title("Crash on save").
+//		Field("Microsoft.VSTS.Common.Priority", 1).
+//		AddRelation(patch.RelParent, parentURL, nil).
+//		Build()
+//
+// Every operation is checked as it is added. The first invalid one is reported by Build, and the
+// operations after it are ignored.
+type Builder struct {
+	ops []webapi.JsonPatchOperation
+	err error
+}
+
+// New returns an empty builder.
+func New() *Builder {
+	return &Builder{}
+}
+
+// Title sets the title, which every work item must have.
+func (b *Builder) Title(title string) *Builder {
+	if title == "" {
+		return b.fail(fmt.Errorf("title cannot be empty"))
+	}
+	return b.Field(FieldTitle, title)
+}
+
+// Description sets the description, as HTML.
+func (b *Builder) Description(description string) *Builder {
+	return b.Field(FieldDescription, description)
+}
+
+// AssignedTo sets the user the work item is assigned to.
+func (b *Builder) AssignedTo(assignedTo string) *Builder {
+	return b.Field(FieldAssignedTo, assignedTo)
+}
+
+// Area sets the area path.
+func (b *Builder) Area(path string) *Builder {
+	return b.Field(FieldAreaPath, path)
+}
+
+// Iteration sets the iteration path.
+func (b *Builder) Iteration(path string) *Builder {
+	return b.Field(FieldIterationPath, path)
+}
+
+// Tags sets the tags, replacing those the work item has.
+func (b *Builder) Tags(tags ...string) *Builder {
+	return b.Field(FieldTags, strings.Join(tags, "; "))
+}
+
+// Field sets a field, given by reference name, such as "Microsoft.VSTS.Common.Priority".
+func (b *Builder) Field(ref string, value interface{}) *Builder {
+	return b.add(webapi.OperationValues.Add, "/fields/"+ref, value)
+}
+
+// ReplaceField replaces the value of a field the work item already has.
+func (b *Builder) ReplaceField(ref string, value interface{}) *Builder {
+	return b.add(webapi.OperationValues.Replace, "/fields/"+ref, value)
+}
+
+// RemoveField clears a field.
+func (b *Builder) RemoveField(ref string) *Builder {
+	return b.add(webapi.OperationValues.Remove, "/fields/"+ref, nil)
+}
+
+// UpdateField sets a field to a non-empty value, or clears it for an empty one. A nil value
+// leaves the field untouched.
+func (b *Builder) UpdateField(ref string, value *string) *Builder {
+	switch {
+	case value == nil:
+		return b
+	case *value == "":
+		return b.RemoveField(ref)
+	default:
+		return b.Field(ref, *value)
+	}
+}
+
+// AddRelation links the work item to the resource at url, such as the REST API URL of another
+// work item. attrs, such as a comment, may be nil.
+func (b *Builder) AddRelation(rel, url string, attrs map[string]interface{}) *Builder {
+	if rel == "" || url == "" {
+		return b.fail(fmt.Errorf("Invalid relation: both the link type and the URL are required."))
+	}
+	relation := map[string]interface{}{"rel": rel, "url": url}
+	if attrs != nil {
+		relation["attributes"] = attrs
+	}
+	return b.add(webapi.OperationValues.Add, "/relations/-", relation)
+}
+
+// RemoveRelation removes the relation at the given index of the work item's relations.
+func (b *Builder) RemoveRelation(index int) *Builder {
+	return b.add(webapi.OperationValues.Remove, "/relations/"+strconv.Itoa(index), nil)
+}
+
+// Test makes the whole document fail unless the value at path equals value, e.g. Test("/rev", 7)
+// so that an update fails if the work item changed since revision 7.
+func (b *Builder) Test(path string, value interface{}) *Builder {
+	return b.add(webapi.OperationValues.Test, path, value)
+}
+
+// Operations appends operations built elsewhere, checking them as the other methods do.
+func (b *Builder) Operations(ops ...webapi.JsonPatchOperation) *Builder {
+	for _, op := range ops {
+		if op.Op == nil || op.Path == nil {
+			return b.fail(fmt.Errorf("Invalid patch operation: both op and path are required."))
+		}
+		b.add(*op.Op, *op.Path, op.Value)
+	}
+	return b
+}
+
+// Len returns the number of operations added so far.
+func (b *Builder) Len() int {
+	return len(b.ops)
+}
+
+// Build returns the patch document, or the first invalid operation.
+func (b *Builder) Build() ([]webapi.JsonPatchOperation, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.ops, nil
+}
+
+// add appends an operation after checking it, unless an earlier one was invalid.
+func (b *Builder) add(op webapi.Operation, path string, value interface{}) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if err := validate(op, path); err != nil {
+		return b.fail(err)
+	}
+	b.ops = append(b.ops, NewOperation(op, path, value))
+	return b
+}
+
+// fail records the first error.
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// validate checks that the operation applies to a path of a work item: a field, a relation, or
+// the revision or ID in a test.
+func validate(op webapi.Operation, path string) error {
+	switch op {
+	case webapi.OperationValues.Add, webapi.OperationValues.Remove, webapi.OperationValues.Replace, webapi.OperationValues.Test:
+	default:
+		return fmt.Errorf("Invalid patch operation: '%s' on %s. Use add, remove, replace or test.", op, path)
+	}
+
+	switch {
+	case strings.HasPrefix(path, "/fields/"):
+		ref := strings.TrimPrefix(path, "/fields/")
+		if ref == "" || strings.ContainsAny(ref, "/ \t\n") {
+			return fmt.Errorf("Invalid field reference name: '%s'. Use a name such as 'Microsoft.VSTS.Common.Priority'.", ref)
+		}
+		return nil
+	case path == "/relations/-":
+		if op != webapi.OperationValues.Add {
+			return fmt.Errorf("Invalid patch operation: '%s' on %s. Relations are appended with add.", op, path)
+		}
+		return nil
+	case strings.HasPrefix(path, "/relations/"):
+		if index, err := strconv.Atoi(strings.TrimPrefix(path, "/relations/")); err != nil || index < 0 {
+			return fmt.Errorf("Invalid relation index in patch path: '%s'.", path)
+		}
+		if op == webapi.OperationValues.Add {
+			return fmt.Errorf("Invalid patch operation: '%s' on %s. Use /relations/- to add a relation.", op, path)
+		}
+		return nil
+	case path == "/rev" || path == "/id":
+		if op != webapi.OperationValues.Test {
+			return fmt.Errorf("Invalid patch operation: '%s' on %s. The revision and ID can only be tested.", op, path)
+		}
+		return nil
+	}
+	return fmt.Errorf("Invalid patch path: '%s'. Use /fields/<reference name>, /relations/- or /relations/<index>.", path)
+}
+
+// NewOperation builds a single JSON patch operation.
+func NewOperation(op webapi.Operation, path string, value interface{}) webapi.JsonPatchOperation {
+	return webapi.JsonPatchOperation{
+		Op:    &op,
+		Path:  &path,
+		Value: value,
+	}
+}
+
+// FieldValue returns the value that patchDoc sets for the field with the given reference name, or nil.
+// When several operations set the field, the last one wins, as it does on the server.
+func FieldValue(patchDoc []webapi.JsonPatchOperation, ref string) interface{} {
+	var value interface{}
+	for _, op := range patchDoc {
+		if op.Path != nil && strings.EqualFold(*op.Path, "/fields/"+ref) {
+			value = op.Value
+		}
+	}
+	return value
+}
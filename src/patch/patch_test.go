@@ -0,0 +1,119 @@
+package patch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+)
+
+func TestBuilder(t *testing.T) {
+	patchDoc, err := New().
+		Test("/rev", 3).
+		Title("Crash on save").
+		Field("Microsoft.VSTS.Common.Priority", 1).
+		RemoveField("System.AssignedTo").
+		AddRelation(RelParent, "https://dev.azure.com/org/proj/_apis/wit/workItems/1", map[string]interface{}{"comment": "split"}).
+		RemoveRelation(0).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		op    webapi.Operation
+		path  string
+		value interface{}
+	}{
+		{webapi.OperationValues.Test, "/rev", 3},
+		{webapi.OperationValues.Add, "/fields/System.Title", "Crash on save"},
+		{webapi.OperationValues.Add, "/fields/Microsoft.VSTS.Common.Priority", 1},
+		{webapi.OperationValues.Remove, "/fields/System.AssignedTo", nil},
+		{webapi.OperationValues.Add, "/relations/-", map[string]interface{}{
+			"rel":        RelParent,
+			"url":        "https://dev.azure.com/org/proj/_apis/wit/workItems/1",
+			"attributes": map[string]interface{}{"comment": "split"},
+		}},
+		{webapi.OperationValues.Remove, "/relations/0", nil},
+	}
+	if len(patchDoc) != len(want) {
+		t.Fatalf("expected %d operations, got %d", len(want), len(patchDoc))
+	}
+	for i, w := range want {
+		op := patchDoc[i]
+		if *op.Op != w.op || *op.Path != w.path || !reflect.DeepEqual(op.Value, w.value) {
+			t.Errorf("operation %d: expected %s %s %v, got %s %s %v", i, w.op, w.path, w.value, *op.Op, *op.Path, op.Value)
+		}
+	}
+}
+
+func TestBuilder_UpdateField(t *testing.T) {
+	value, empty := "Alice", ""
+	b := New().UpdateField(FieldAssignedTo, nil)
+	if b.Len() != 0 {
+		t.Fatalf("expected a nil value to add nothing, got %d operations", b.Len())
+	}
+	patchDoc, err := b.UpdateField(FieldAssignedTo, &value).UpdateField(FieldDescription, &empty).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *patchDoc[0].Op != webapi.OperationValues.Add || patchDoc[0].Value != "Alice" {
+		t.Errorf("expected an add of the value, got %s %v", *patchDoc[0].Op, patchDoc[0].Value)
+	}
+	if *patchDoc[1].Op != webapi.OperationValues.Remove || patchDoc[1].Value != nil {
+		t.Errorf("expected a remove for an empty value, got %s %v", *patchDoc[1].Op, patchDoc[1].Value)
+	}
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		want    string
+	}{
+		{"empty title", New().Title(""), "title cannot be empty"},
+		{"field with a space", New().Field("Story Points", 3), "Invalid field reference name: 'Story Points'"},
+		{"empty field", New().RemoveField(""), "Invalid field reference name: ''"},
+		{"unknown path", New().Test("/state", "New"), "Invalid patch path: '/state'"},
+		{"relation index", New().Operations(NewOperation(webapi.OperationValues.Remove, "/relations/x", nil)), "Invalid relation index"},
+		{"negative relation index", New().RemoveRelation(-1), "Invalid relation index"},
+		{"add at a relation index", New().Operations(NewOperation(webapi.OperationValues.Add, "/relations/0", nil)), "Use /relations/- to add a relation."},
+		{"remove appended relation", New().Operations(NewOperation(webapi.OperationValues.Remove, "/relations/-", nil)), "Relations are appended with add."},
+		{"replace revision", New().Operations(NewOperation(webapi.OperationValues.Replace, "/rev", 2)), "can only be tested"},
+		{"unsupported op", New().Operations(NewOperation(webapi.OperationValues.Move, "/fields/System.Title", nil)), "Use add, remove, replace or test."},
+		{"relation without URL", New().AddRelation(RelParent, "", nil), "Invalid relation"},
+		{"missing op", New().Operations(webapi.JsonPatchOperation{}), "both op and path are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// The first error is kept and later operations are ignored.
+	b := New().Field("System.Title", "ok").Title("").Field("bad path", 1)
+	if _, err := b.Build(); err == nil || err.Error() != "title cannot be empty" {
+		t.Errorf("expected the first error, got %v", err)
+	}
+	if b.Len() != 1 {
+		t.Errorf("expected the operations after the error to be ignored, got %d", b.Len())
+	}
+}
+
+func TestFieldValue(t *testing.T) {
+	patchDoc := []webapi.JsonPatchOperation{
+		NewOperation(webapi.OperationValues.Add, "/fields/System.Title", "Crash"),
+		NewOperation(webapi.OperationValues.Add, "/fields/Microsoft.VSTS.Common.Priority", 2),
+		NewOperation(webapi.OperationValues.Replace, "/fields/Microsoft.VSTS.Common.Priority", 1),
+	}
+	if got := FieldValue(patchDoc, "microsoft.vsts.common.priority"); got != 1 {
+		t.Errorf("expected the last value of the field, got %v", got)
+	}
+	if got := FieldValue(patchDoc, "System.State"); got != nil {
+		t.Errorf("expected nil for a field that is not set, got %v", got)
+	}
+}